@@ -0,0 +1,94 @@
+package builder
+
+import "strings"
+
+// Aggregate is an aggregate function over a column, usable in the select
+// list through SelectAggregate and in HAVING through Compare.
+type Aggregate struct {
+	function string
+	column   string
+	distinct bool
+	alias    string
+}
+
+func Count(column string) Aggregate {
+	return Aggregate{function: "COUNT", column: column}
+}
+
+func CountDistinct(column string) Aggregate {
+	return Aggregate{function: "COUNT", column: column, distinct: true}
+}
+
+func Sum(column string) Aggregate {
+	return Aggregate{function: "SUM", column: column}
+}
+
+func Avg(column string) Aggregate {
+	return Aggregate{function: "AVG", column: column}
+}
+
+func Min(column string) Aggregate {
+	return Aggregate{function: "MIN", column: column}
+}
+
+func Max(column string) Aggregate {
+	return Aggregate{function: "MAX", column: column}
+}
+
+func (a Aggregate) As(alias string) Aggregate {
+	a.alias = alias
+	return a
+}
+
+// Expression renders the aggregate call without its alias.
+func (a Aggregate) Expression() string {
+	var s strings.Builder
+	s.WriteString(a.function)
+	s.WriteString("(")
+	if a.distinct {
+		s.WriteString("DISTINCT ")
+	}
+	if a.column == "*" {
+		s.WriteString(a.column)
+	} else {
+		s.WriteString(quoteKey(a.column))
+	}
+	s.WriteString(")")
+	return s.String()
+}
+
+func (a Aggregate) String() string {
+	if a.alias == "" {
+		return a.Expression()
+	}
+	return a.Expression() + " AS " + quoteKey(a.alias)
+}
+
+// Compare turns the aggregate into a predicate, e.g.
+// Having(Count("*").Compare(">", 5)).
+func (a Aggregate) Compare(operator Operator, value interface{}) Expr {
+	return &aggregateCondition{aggregate: a, operator: operator, value: value}
+}
+
+type aggregateCondition struct {
+	aggregate Aggregate
+	operator  Operator
+	value     interface{}
+}
+
+func (c *aggregateCondition) expr(b *builder) (string, []interface{}) {
+	b.checkIdentifiers(c.aggregate.column)
+	stmt, values, err := buildComparison(c.aggregate.Expression(), c.operator, c.value)
+	if err != nil {
+		b.fail(err)
+	}
+	return stmt, values
+}
+
+func (b *builder) SelectAggregate(aggregates ...Aggregate) Builder {
+	for _, aggregate := range aggregates {
+		b.checkIdentifiers(aggregate.column)
+		b.Select(aggregate.String())
+	}
+	return b
+}
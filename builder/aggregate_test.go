@@ -0,0 +1,50 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestHaving(t *testing.T) {
+	query, values := New().
+		Select("`user_id`").
+		SelectAggregate(
+			Count("*").As("orders"),
+			CountDistinct("product_id").As("products"),
+			Sum("o.amount").As("total"),
+			Avg("amount"),
+			Min("amount"),
+			Max("amount").As("largest"),
+		).
+		From("`order`", "o").
+		Equal("status", "paid").
+		Group("user_id").
+		Having(Count("*").Compare(">", 5)).
+		Having(Or(
+			Condition{Key: "total", Operator: ">=", Value: 1000},
+			Condition{Key: "products", Operator: ">", Value: 3},
+		)).
+		Build()
+	expected := "SELECT `user_id`,COUNT(*) AS `orders`,COUNT(DISTINCT `product_id`) AS `products`,SUM(o.`amount`) AS `total`," +
+		"AVG(`amount`),MIN(`amount`),MAX(`amount`) AS `largest` FROM `order` o WHERE `status` = ? GROUP BY `user_id` " +
+		"HAVING COUNT(*) > ? AND (`total` >= ? OR `products` > ?)"
+	if query != expected {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"paid", 5, 1000, 3}) {
+		t.Fatalf("wrong values %v", values)
+	}
+}
+
+func TestRollup(t *testing.T) {
+	query, _ := New().
+		Select("`year`,`month`").
+		SelectAggregate(Sum("amount").As("total")).
+		From("`sale`").
+		Groups([]string{"year", "month"}).
+		Rollup().
+		Build()
+	if query != "SELECT `year`,`month`,SUM(`amount`) AS `total` FROM `sale` GROUP BY `year`,`month` WITH ROLLUP" {
+		t.Fatalf("got %q", query)
+	}
+}
@@ -0,0 +1,175 @@
+package builder
+
+import (
+	"strings"
+	"time"
+)
+
+const (
+	// MaxPlaceholders is the number of placeholders MySQL accepts in a
+	// single prepared statement.
+	MaxPlaceholders = 65535
+	// DefaultMaxAllowedPacket mirrors the smallest max_allowed_packet
+	// shipped by MySQL, used unless MaxAllowedPacket says otherwise.
+	DefaultMaxAllowedPacket = 4 << 20
+)
+
+// Batch is one executable chunk of a multi-row statement.
+type Batch struct {
+	Query string
+	Args  []interface{}
+}
+
+func (b *builder) MaxAllowedPacket(bytes int) Builder {
+	if bytes > 0 {
+		b.maxAllowedPacket = bytes
+	}
+	return b
+}
+
+// InsertMany renders rows as multi-row INSERT statements, split so each
+// chunk stays under the placeholder limit and max_allowed_packet. Without
+// columns every key found in rows is inserted, missing ones as NULL.
+func (b *builder) InsertMany(rows []map[string]interface{}, columns ...string) []Batch {
+	verb, suffix := b.insertClause()
+	batches, err := buildInsertMany(verb, b.source[0]["table"], rows, columns, nil, suffix, b.packetSize())
+	if err != nil {
+		b.fail(err)
+	}
+	return b.localizeBatches(batches)
+}
+
+// UpsertMany is InsertMany resolving duplicate keys the way Upsert does.
+func (b *builder) UpsertMany(rows []map[string]interface{}, columns ...string) []Batch {
+	strategy := b.conflictStrategy()
+	batches, err := buildInsertMany("INSERT INTO ", b.source[0]["table"], rows, columns, &strategy, "", b.packetSize())
+	if err != nil {
+		b.fail(err)
+	}
+	return b.localizeBatches(batches)
+}
+
+func (b *builder) localizeBatches(batches []Batch) []Batch {
+	for i := range batches {
+		batches[i].Query = rebind(b.dialect, batches[i].Query)
+		batches[i].Args = b.localize(batches[i].Args)
+	}
+	return batches
+}
+
+func (b *builder) packetSize() int {
+	if b.maxAllowedPacket > 0 {
+		return b.maxAllowedPacket
+	}
+	return DefaultMaxAllowedPacket
+}
+
+func buildInsertMany(verb string, table string, rows []map[string]interface{}, columns []string, upsert *conflict, suffix string, packet int) ([]Batch, error) {
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	if len(columns) == 0 {
+		union := make(map[string]interface{})
+		for _, row := range rows {
+			for key := range row {
+				union[key] = nil
+			}
+		}
+		columns = sortedKeys(union)
+	}
+	if len(columns) == 0 {
+		return nil, nil
+	}
+	keys := make([]string, len(columns))
+	for i, column := range columns {
+		keys[i] = quoteKey(column)
+	}
+	var head strings.Builder
+	head.WriteString(verb)
+	head.WriteString(table)
+	head.WriteString("(")
+	head.WriteString(strings.Join(keys, ","))
+	head.WriteString(") VALUES ")
+	var tail strings.Builder
+	var tailValues []interface{}
+	tail.WriteString(suffix)
+	if upsert != nil {
+		clause, values, err := buildConflictClause(*upsert, keys, nil)
+		if err != nil {
+			return nil, err
+		}
+		tail.WriteString(clause)
+		tailValues = values
+	}
+	tail.WriteString(";")
+	batches := make([]Batch, 0)
+	var query strings.Builder
+	var args []interface{}
+	size, count := 0, 0
+	flush := func() {
+		if count == 0 {
+			return
+		}
+		query.WriteString(tail.String())
+		args = append(args, tailValues...)
+		batches = append(batches, Batch{Query: query.String(), Args: args})
+		query.Reset()
+		args = nil
+		count = 0
+	}
+	for _, row := range rows {
+		var placeholder strings.Builder
+		var values []interface{}
+		placeholder.WriteString("(")
+		for i, column := range columns {
+			if i > 0 {
+				placeholder.WriteString(",")
+			}
+			value := row[column]
+			if raw, ok := value.(RawExpr); ok && raw.err != nil {
+				return nil, raw.err
+			}
+			if value == nil {
+				placeholder.WriteString("?")
+				values = append(values, nil)
+				continue
+			}
+			values = append(values, writeValue(&placeholder, value)...)
+		}
+		placeholder.WriteString(")")
+		rowSize := placeholder.Len() + 1
+		for _, value := range values {
+			rowSize += argSize(value)
+		}
+		if count > 0 && (len(args)+len(values)+len(tailValues) > MaxPlaceholders || size+rowSize > packet) {
+			flush()
+		}
+		if count == 0 {
+			query.WriteString(head.String())
+			size = head.Len() + tail.Len()
+		} else {
+			query.WriteString(",")
+		}
+		query.WriteString(placeholder.String())
+		args = append(args, values...)
+		size += rowSize
+		count++
+	}
+	flush()
+	return batches, nil
+}
+
+// argSize estimates how many bytes value takes on the wire.
+func argSize(value interface{}) int {
+	switch v := value.(type) {
+	case nil:
+		return 1
+	case string:
+		return len(v) + 9
+	case []byte:
+		return len(v) + 9
+	case time.Time:
+		return 12
+	}
+	return 9
+}
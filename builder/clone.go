@@ -0,0 +1,90 @@
+package builder
+
+import "strings"
+
+// Clone returns an independent copy of b, so a base query can be branched
+// without the branches seeing each other's clauses:
+//
+//	base := New().Select("*").From("`post`").Equal("tenant_id", id)
+//	drafts := base.Clone().Equal("status", "draft")
+//	published := base.Clone().Equal("status", "published")
+func (b *builder) Clone() Builder {
+	return b.clone()
+}
+
+func (b *builder) clone() *builder {
+	c := *b
+	copyStatement(&c.withStatement, &b.withStatement)
+	copyStatement(&c.selectStatement, &b.selectStatement)
+	copyStatement(&c.whereStatement, &b.whereStatement)
+	copyStatement(&c.orderStatement, &b.orderStatement)
+	copyStatement(&c.groupStatement, &b.groupStatement)
+	copyStatement(&c.havingStatement, &b.havingStatement)
+	copyStatement(&c.windowStatement, &b.windowStatement)
+	c.operator = append(b.operator[:0:0], b.operator...)
+	c.withValues = append(b.withValues[:0:0], b.withValues...)
+	c.selectValues = append(b.selectValues[:0:0], b.selectValues...)
+	c.havingValues = append(b.havingValues[:0:0], b.havingValues...)
+	c.windowValues = append(b.windowValues[:0:0], b.windowValues...)
+	c.orderValues = append(b.orderValues[:0:0], b.orderValues...)
+	c.values = append(b.values[:0:0], b.values...)
+	c.lockTables = append(b.lockTables[:0:0], b.lockTables...)
+	c.deleteTargets = append(b.deleteTargets[:0:0], b.deleteTargets...)
+	c.columns = append(b.columns[:0:0], b.columns...)
+	c.identifiers = append(b.identifiers[:0:0], b.identifiers...)
+	c.conflict.assignments = append(b.conflict.assignments[:0:0], b.conflict.assignments...)
+	c.conflict.target = append(b.conflict.target[:0:0], b.conflict.target...)
+	c.upsert = copyData(b.upsert)
+	c.update = copyData(b.update)
+	c.insert = copyData(b.insert)
+	if b.source != nil {
+		c.source = make([]map[string]string, len(b.source))
+		for i, source := range b.source {
+			c.source[i] = make(map[string]string, len(source))
+			for key, value := range source {
+				c.source[i][key] = value
+			}
+		}
+	}
+	if b.sourceValues != nil {
+		c.sourceValues = make(map[int][]interface{}, len(b.sourceValues))
+		for key, values := range b.sourceValues {
+			c.sourceValues[key] = append(values[:0:0], values...)
+		}
+	}
+	if b.compound != nil {
+		c.compound = make([]Builder, len(b.compound))
+		for i, part := range b.compound {
+			c.compound[i] = part.Clone()
+		}
+	}
+	if b.insertSelect != nil {
+		c.insertSelect = b.insertSelect.Clone()
+	}
+	if b.allow != nil {
+		c.allow = make(map[string]map[string]bool, len(b.allow))
+		for table, columns := range b.allow {
+			c.allow[table] = make(map[string]bool, len(columns))
+			for column := range columns {
+				c.allow[table][column] = true
+			}
+		}
+	}
+	return &c
+}
+
+func copyStatement(dst, src *strings.Builder) {
+	*dst = strings.Builder{}
+	dst.WriteString(src.String())
+}
+
+func copyData(data map[string]interface{}) map[string]interface{} {
+	if data == nil {
+		return nil
+	}
+	c := make(map[string]interface{}, len(data))
+	for key, value := range data {
+		c[key] = value
+	}
+	return c
+}
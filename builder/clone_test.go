@@ -0,0 +1,54 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClone(t *testing.T) {
+	base := New().Select("*").From("`post`", "p").Equal("tenant_id", 1).Order(OrderBy{Column: "id", Direction: "DESC"})
+	baseQuery, baseValues := base.Build()
+
+	drafts := base.Clone().Equal("status", "draft").Size(10)
+	published := base.Clone().Join("`user`", "u.`id` = p.`user_id`", "u").Equal("status", "published").Group("u.id")
+	tenant := base.Clone().Reset("where").Equal("tenant_id", 2)
+	base.Equal("deleted", 0)
+
+	cases := []struct {
+		name   string
+		b      Builder
+		query  string
+		values []interface{}
+	}{
+		{"drafts", drafts, "SELECT * FROM `post` p WHERE `tenant_id` = ? AND `status` = ? ORDER BY `id` DESC LIMIT 10 ", []interface{}{1, "draft"}},
+		{"published", published, "SELECT * FROM `post` p JOIN `user` u  ON u.`id` = p.`user_id` WHERE `tenant_id` = ? AND `status` = ? GROUP BY u.`id` ORDER BY `id` DESC", []interface{}{1, "published"}},
+		{"tenant", tenant, "SELECT * FROM `post` p WHERE `tenant_id` = ? ORDER BY `id` DESC", []interface{}{2}},
+		{"base", base, "SELECT * FROM `post` p WHERE `tenant_id` = ? AND `deleted` = ? ORDER BY `id` DESC", []interface{}{1, 0}},
+	}
+	for _, c := range cases {
+		query, values := c.b.Build()
+		if query != c.query {
+			t.Errorf("%s:\n got %q\nwant %q", c.name, query, c.query)
+		}
+		if !reflect.DeepEqual(values, c.values) {
+			t.Errorf("%s: got %#v", c.name, values)
+		}
+	}
+	if baseQuery != "SELECT * FROM `post` p WHERE `tenant_id` = ? ORDER BY `id` DESC" || !reflect.DeepEqual(baseValues, []interface{}{1}) {
+		t.Fatalf("got %q %#v", baseQuery, baseValues)
+	}
+
+	list := base.Clone().Page(2).Size(5)
+	count, _ := list.BuildCount()
+	if count != "SELECT COUNT(*) FROM `post` p WHERE `tenant_id` = ? AND `deleted` = ?" {
+		t.Fatalf("got %q", count)
+	}
+
+	original := New().Table("`post`").Upsert(map[string]interface{}{"id": 1, "title": "a"}).ConflictTarget("id")
+	copied := original.Clone().(*builder)
+	copied.upsert["title"] = "b"
+	copied.conflict.target[0] = "slug"
+	if query, values := original.Build(); query != "INSERT INTO `post`(`id`,`title`) VALUES (?,?) ON DUPLICATE KEY UPDATE `id`=VALUES(`id`),`title`=VALUES(`title`);" || values[1] != "a" {
+		t.Fatalf("got %q %#v", query, values)
+	}
+}
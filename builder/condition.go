@@ -1,7 +1,136 @@
 package builder
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	ErrInvalidOperator = errors.New("invalid operator")
+	ErrInvalidValue    = errors.New("invalid value for operator")
+)
+
+// Operator is the comparison of a Condition. Only the operators below are
+// rendered; any other value makes the condition fail.
+type Operator string
+
+const (
+	Eq           Operator = "="
+	Ne           Operator = "<>"
+	Lt           Operator = "<"
+	Lte          Operator = "<="
+	Gt           Operator = ">"
+	Gte          Operator = ">="
+	Like         Operator = "LIKE"
+	NotLike      Operator = "NOT LIKE"
+	IsNull       Operator = "IS NULL"
+	IsNotNull    Operator = "IS NOT NULL"
+	Between      Operator = "BETWEEN"
+	In           Operator = "IN"
+	NotIn        Operator = "NOT IN"
+	Regexp       Operator = "REGEXP"
+	JSONContains Operator = "JSON_CONTAINS"
+)
+
+var operatorAliases = map[string]Operator{
+	"=": Eq, "==": Eq, "EQ": Eq,
+	"<>": Ne, "!=": Ne, "NE": Ne,
+	"<": Lt, "LT": Lt,
+	"<=": Lte, "LTE": Lte,
+	">": Gt, "GT": Gt,
+	">=": Gte, "GTE": Gte,
+	"LIKE": Like, "NOT LIKE": NotLike,
+	"IS NULL": IsNull, "IS NOT NULL": IsNotNull,
+	"BETWEEN": Between,
+	"IN":      In, "NOT IN": NotIn, "NIN": NotIn,
+	"REGEXP": Regexp, "RLIKE": Regexp,
+	"JSON_CONTAINS": JSONContains,
+}
+
 type Condition struct {
-	Operator string
+	Operator Operator
 	Key      string
 	Value    interface{}
 }
+
+// ParseOperator reads the loosely written operators of older callers, such
+// as "like", "LIKE " or "!=", ignoring case and extra spaces.
+func ParseOperator(s string) (Operator, error) {
+	name := strings.ToUpper(strings.Join(strings.Fields(s), " "))
+	if operator, ok := operatorAliases[name]; ok {
+		return operator, nil
+	}
+	return "", fmt.Errorf("%w: %q", ErrInvalidOperator, s)
+}
+
+// validate checks value has the shape operator needs: nothing for the null
+// checks, two items for Between, a list for In and NotIn, a string for the
+// pattern operators and a single value otherwise.
+func (o Operator) validate(value interface{}) error {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w %s: %s", ErrInvalidValue, o, reason)
+	}
+	if _, ok := rawValue(value); ok {
+		switch o {
+		case IsNull, IsNotNull, Between, In, NotIn:
+			return invalid("raw expressions are not supported")
+		}
+		return nil
+	}
+	list := value != nil && isList(value)
+	switch o {
+	case IsNull, IsNotNull:
+		if value != nil {
+			return invalid("takes no value")
+		}
+	case Between:
+		if !list || reflect.ValueOf(value).Len() != 2 {
+			return invalid("takes two values")
+		}
+	case In, NotIn:
+		if !list {
+			return invalid("takes a list")
+		}
+	case Like, NotLike, Regexp:
+		if _, ok := value.(string); !ok {
+			return invalid("takes a string")
+		}
+	case JSONContains:
+		if value == nil {
+			return invalid("takes a value")
+		}
+	case Eq, Ne:
+		if list {
+			return invalid("takes a single value")
+		}
+	default:
+		if value == nil || list {
+			return invalid("takes a single value")
+		}
+	}
+	return nil
+}
+
+func isList(value interface{}) bool {
+	if _, ok := value.([]byte); ok {
+		return false
+	}
+	kind := reflect.TypeOf(value).Kind()
+	return kind == reflect.Slice || kind == reflect.Array
+}
+
+// jsonDocument returns value as the JSON text JSON_CONTAINS expects.
+func jsonDocument(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string, []byte:
+		return v, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
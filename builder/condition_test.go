@@ -0,0 +1,66 @@
+package builder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParseOperator(t *testing.T) {
+	cases := map[string]Operator{
+		"like":          Like,
+		"LIKE ":         Like,
+		"not   like":    NotLike,
+		"!=":            Ne,
+		"is null":       IsNull,
+		"rlike":         Regexp,
+		"json_contains": JSONContains,
+	}
+	for s, want := range cases {
+		if got, err := ParseOperator(s); err != nil || got != want {
+			t.Errorf("ParseOperator(%q) = %q, %v", s, got, err)
+		}
+	}
+	if _, err := ParseOperator("=; --"); !errors.Is(err, ErrInvalidOperator) {
+		t.Fatalf("got %v", err)
+	}
+}
+
+func TestConditionOperators(t *testing.T) {
+	query, values := New().Select("*").From("`post`").Compare([]Condition{
+		{Key: "deleted_at", Operator: Eq, Value: nil},
+		{Key: "owner_id", Operator: IsNotNull},
+		{Key: "score", Operator: Between, Value: []int{1, 5}},
+		{Key: "status", Operator: NotIn, Value: []string{"a", "b"}},
+		{Key: "tags", Operator: JSONContains, Value: []string{"go"}},
+		{Key: "title", Operator: "not like", Value: "%x%"},
+	}).Build()
+	expected := "SELECT * FROM `post` WHERE `deleted_at` IS NULL AND `owner_id` IS NOT NULL AND `score` BETWEEN ? AND ? AND " +
+		"`status` NOT IN (?,?) AND JSON_CONTAINS(`tags`, ?) AND `title` NOT LIKE ?"
+	if query != expected {
+		t.Fatalf("query:\n got %q\nwant %q", query, expected)
+	}
+	if !reflect.DeepEqual(values, []interface{}{1, 5, "a", "b", `["go"]`, "%x%"}) {
+		t.Fatalf("got %#v", values)
+	}
+
+	query, _ = New().Select("*").From("`post`").Where(Condition{Key: "id", Operator: In, Value: []int{}}).Build()
+	if query != "SELECT * FROM `post` WHERE FALSE" {
+		t.Fatalf("got %q", query)
+	}
+
+	invalid := []Condition{
+		{Key: "id", Operator: "=; --", Value: 1},
+		{Key: "id", Operator: Between, Value: 1},
+		{Key: "id", Operator: In, Value: 1},
+		{Key: "id", Operator: IsNull, Value: 1},
+		{Key: "id", Operator: Like, Value: 1},
+	}
+	for _, condition := range invalid {
+		b := New().Select("*").From("`post`").Where(condition)
+		query, values := b.Build()
+		if query != "SELECT * FROM `post` WHERE FALSE" || len(values) != 0 || b.Err() == nil {
+			t.Errorf("%v: got %q %v %v", condition, query, values, b.Err())
+		}
+	}
+}
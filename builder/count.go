@@ -0,0 +1,30 @@
+package builder
+
+// BuildCount renders the query counting the rows Build would return without
+// its pagination. Ordering, limits and locks are dropped; grouped, distinct
+// and compound queries are counted over a derived table.
+func (b *builder) BuildCount() (string, []interface{}) {
+	inner := b.clone()
+	inner.orderStatement.Reset()
+	inner.orderValues = nil
+	inner.page = 0
+	inner.size = 0
+	inner.lock = ""
+	inner.lockTables = nil
+	inner.lockWait = ""
+	inner.explain = false
+	var query string
+	var values []interface{}
+	if len(b.compound) > 0 || b.distinct || b.groupStatement.Len() > 0 || b.havingStatement.Len() > 0 {
+		stmt, tmp := inner.render()
+		query, values = "SELECT COUNT(*) FROM ("+stmt+") AS `count`", tmp
+	} else {
+		inner.selectStatement.Reset()
+		inner.selectStatement.WriteString("COUNT(*)")
+		inner.selectValues = nil
+		inner.windowStatement.Reset()
+		inner.windowValues = nil
+		query, values = inner.render()
+	}
+	return rebind(b.dialect, query), values
+}
@@ -0,0 +1,49 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestBuildCount(t *testing.T) {
+	b := New().Select("p.*").From("`post`", "p").Join("`user`", "u.`id` = p.`user_id`", "u").
+		Equal("u.active", true).
+		Order(OrderBy{Column: "p.id", Direction: "DESC"}).
+		Page(2).Size(10).
+		ForUpdate()
+	query, values := b.BuildCount()
+	if query != "SELECT COUNT(*) FROM `post` p JOIN `user` u  ON u.`id` = p.`user_id` WHERE u.`active` = ?" {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{true}) {
+		t.Fatalf("got %#v", values)
+	}
+	if query, _ := b.Build(); query != "SELECT p.* FROM `post` p JOIN `user` u  ON u.`id` = p.`user_id` WHERE u.`active` = ? ORDER BY p.`id` DESC LIMIT 10  OFFSET 10  FOR UPDATE" {
+		t.Fatalf("count changed the builder: %q", query)
+	}
+
+	query, values = New().Select("`user_id`").From("`post`").Equal("status", "published").
+		Group("user_id").Order(OrderBy{Column: "user_id", Direction: "ASC"}).Size(5).BuildCount()
+	if query != "SELECT COUNT(*) FROM (SELECT `user_id` FROM `post` WHERE `status` = ? GROUP BY `user_id`) AS `count`" {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"published"}) {
+		t.Fatalf("got %#v", values)
+	}
+
+	query, _ = New().Select("`tag`").Distinct().From("`post_tag`").BuildCount()
+	if query != "SELECT COUNT(*) FROM (SELECT DISTINCT `tag` FROM `post_tag`) AS `count`" {
+		t.Fatalf("got %q", query)
+	}
+
+	query, values = UnionAll(
+		New().Select("`id`").From("`post`").Equal("a", 1),
+		New().Select("`id`").From("`page`").Equal("b", 2),
+	).Size(3).BuildCount()
+	if query != "SELECT COUNT(*) FROM ((SELECT `id` FROM `post` WHERE `a` = ?) UNION ALL (SELECT `id` FROM `page` WHERE `b` = ?)) AS `count`" {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{1, 2}) {
+		t.Fatalf("got %#v", values)
+	}
+}
@@ -0,0 +1,233 @@
+package builder
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+	ErrCursorSecret  = errors.New("cursor secret is empty")
+)
+
+// After continues a listing from next, see Seek.
+func (b *builder) After(next Next) Builder {
+	return b.Seek(next)
+}
+
+// Seek continues a listing after the row identified by keys, comparing them
+// as a tuple so every column may be ordered in its own direction. The
+// matching ORDER BY is appended in the same order as keys. A nil key value
+// would match no row, so it fails the builder with ErrInvalidCursor.
+func (b *builder) Seek(keys ...Next) Builder {
+	if len(keys) == 0 {
+		return b
+	}
+	for _, key := range keys {
+		if key.Value == nil {
+			b.fail(fmt.Errorf("%w: %s has no value", ErrInvalidCursor, key.Column))
+		}
+	}
+	var stmt strings.Builder
+	var values []interface{}
+	columns := make([]string, len(keys))
+	for i, key := range keys {
+		columns[i] = b.identifier(key.Column)
+	}
+	for i, key := range keys {
+		if i > 0 {
+			stmt.WriteString(" OR ")
+		}
+		if i > 0 {
+			stmt.WriteString("(")
+		}
+		for j := 0; j < i; j++ {
+			stmt.WriteString(columns[j])
+			stmt.WriteString(" = ? AND ")
+			values = append(values, keys[j].Value)
+		}
+		stmt.WriteString(columns[i])
+		if isDescending(key.Direction) {
+			stmt.WriteString(" < ?")
+		} else {
+			stmt.WriteString(" > ?")
+		}
+		values = append(values, key.Value)
+		if i > 0 {
+			stmt.WriteString(")")
+		}
+	}
+	b.conjunction()
+	if len(keys) > 1 {
+		b.whereStatement.WriteString("(")
+		b.whereStatement.WriteString(stmt.String())
+		b.whereStatement.WriteString(")")
+	} else {
+		b.whereStatement.WriteString(stmt.String())
+	}
+	b.values = append(b.values, values...)
+	for i, key := range keys {
+		direction := "ASC"
+		if isDescending(key.Direction) {
+			direction = "DESC"
+		}
+		b.Order(OrderBy{Column: columns[i], Direction: direction})
+	}
+	return b
+}
+
+func isDescending(direction string) bool {
+	return strings.EqualFold(strings.TrimSpace(direction), "DESC")
+}
+
+type cursorKey struct {
+	Column    string          `json:"c"`
+	Direction string          `json:"d,omitempty"`
+	Kind      string          `json:"k"`
+	Value     json.RawMessage `json:"v,omitempty"`
+}
+
+// EncodeCursor serializes keys into an opaque token signed with secret, so
+// it can be handed to API clients as a next cursor and verified by
+// DecodeCursor when it comes back.
+func EncodeCursor(secret []byte, keys ...Next) (string, error) {
+	if len(secret) == 0 {
+		return "", ErrCursorSecret
+	}
+	payload := make([]cursorKey, 0, len(keys))
+	for _, key := range keys {
+		kind, value, err := encodeCursorValue(key.Value)
+		if err != nil {
+			return "", err
+		}
+		payload = append(payload, cursorKey{
+			Column:    key.Column,
+			Direction: key.Direction,
+			Kind:      kind,
+			Value:     value,
+		})
+	}
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	encoding := base64.RawURLEncoding
+	return encoding.EncodeToString(data) + "." + encoding.EncodeToString(sign(secret, data)), nil
+}
+
+// DecodeCursor verifies token against secret and returns the keys it was
+// created from, with values restored to their original Go types.
+func DecodeCursor(secret []byte, token string) ([]Next, error) {
+	if len(secret) == 0 {
+		return nil, ErrCursorSecret
+	}
+	encoding := base64.RawURLEncoding
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return nil, ErrInvalidCursor
+	}
+	data, err := encoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	signature, err := encoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, sign(secret, data)) {
+		return nil, ErrInvalidCursor
+	}
+	var payload []cursorKey
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return nil, ErrInvalidCursor
+	}
+	keys := make([]Next, 0, len(payload))
+	for _, item := range payload {
+		value, err := decodeCursorValue(item.Kind, item.Value)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		keys = append(keys, Next{
+			Column:    item.Column,
+			Direction: item.Direction,
+			Value:     value,
+		})
+	}
+	return keys, nil
+}
+
+func sign(secret, data []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(data)
+	return mac.Sum(nil)
+}
+
+func encodeCursorValue(value interface{}) (string, json.RawMessage, error) {
+	var kind string
+	switch v := value.(type) {
+	case nil:
+		return "", nil, fmt.Errorf("%w: nil value", ErrInvalidCursor)
+	case time.Time:
+		kind, value = "time", v.Format(time.RFC3339Nano)
+	case []byte:
+		kind = "bytes"
+	default:
+		switch reflect.ValueOf(value).Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			kind = "int"
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			kind = "uint"
+		case reflect.Float32, reflect.Float64:
+			kind = "float"
+		case reflect.String:
+			kind = "string"
+		case reflect.Bool:
+			kind = "bool"
+		default:
+			return "", nil, errors.New("unsupported cursor value")
+		}
+	}
+	data, err := json.Marshal(value)
+	return kind, data, err
+}
+
+func decodeCursorValue(kind string, data json.RawMessage) (interface{}, error) {
+	var err error
+	switch kind {
+	case "int":
+		var v int64
+		err = json.Unmarshal(data, &v)
+		return v, err
+	case "uint":
+		var v uint64
+		err = json.Unmarshal(data, &v)
+		return v, err
+	case "float":
+		var v float64
+		err = json.Unmarshal(data, &v)
+		return v, err
+	case "string":
+		var v string
+		err = json.Unmarshal(data, &v)
+		return v, err
+	case "bool":
+		var v bool
+		err = json.Unmarshal(data, &v)
+		return v, err
+	case "bytes":
+		var v []byte
+		err = json.Unmarshal(data, &v)
+		return v, err
+	case "time":
+		var v string
+		if err = json.Unmarshal(data, &v); err != nil {
+			return nil, err
+		}
+		return time.Parse(time.RFC3339Nano, v)
+	}
+	return nil, ErrInvalidCursor
+}
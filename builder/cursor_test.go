@@ -0,0 +1,86 @@
+package builder
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSeek(t *testing.T) {
+	query, values := New().
+		Select("*").
+		From("`post`").
+		Equal("tenant_id", 1).
+		And().
+		Seek(
+			Next{Column: "created_at", Direction: "desc", Value: "2024-01-01 00:00:00"},
+			Next{Column: "id", Direction: "asc", Value: 10},
+		).
+		Size(20).
+		Build()
+	expected := "SELECT * FROM `post` WHERE `tenant_id` = ? AND " +
+		"(`created_at` < ? OR (`created_at` = ? AND `id` > ?)) " +
+		"ORDER BY `created_at` DESC,`id` ASC LIMIT 20 "
+	if query != expected {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{1, "2024-01-01 00:00:00", "2024-01-01 00:00:00", 10}) {
+		t.Fatalf("wrong values %v", values)
+	}
+}
+
+func TestAfter(t *testing.T) {
+	query, values := New().Select("*").From("`post`").After(Next{Column: "id", Value: 10}).Build()
+	if query != "SELECT * FROM `post` WHERE `id` > ? ORDER BY `id` ASC" {
+		t.Fatalf("got %q", query)
+	}
+	if len(values) != 1 || values[0] != 10 {
+		t.Fatalf("wrong values %v", values)
+	}
+}
+
+func TestCursor(t *testing.T) {
+	secret := []byte("secret")
+	at := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	keys := []Next{
+		{Column: "created_at", Direction: "DESC", Value: at},
+		{Column: "id", Direction: "ASC", Value: 10},
+		{Column: "code", Value: "x"},
+	}
+	token, err := EncodeCursor(secret, keys...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeCursor(secret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded) != 3 {
+		t.Fatalf("wrong keys %v", decoded)
+	}
+	if tmp, ok := decoded[0].Value.(time.Time); !ok || !tmp.Equal(at) {
+		t.Fatalf("wrong time %v", decoded[0].Value)
+	}
+	if decoded[1].Value != int64(10) || decoded[2].Value != "x" {
+		t.Fatalf("wrong values %v", decoded)
+	}
+	if _, err := DecodeCursor([]byte("other"), token); err != ErrInvalidCursor {
+		t.Fatal("cursor signed with another secret was accepted")
+	}
+	tampered := strings.Replace(token, token[:4], "AAAA", 1)
+	if _, err := DecodeCursor(secret, tampered); err != ErrInvalidCursor {
+		t.Fatal("tampered cursor was accepted")
+	}
+}
+
+func TestSeekNil(t *testing.T) {
+	b := New().Select("*").From("`post`").Seek(Next{Column: "published_at", Value: nil}, Next{Column: "id", Value: 10})
+	if !errors.Is(b.Err(), ErrInvalidCursor) {
+		t.Fatalf("got %v", b.Err())
+	}
+	if _, err := EncodeCursor([]byte("secret"), Next{Column: "id"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("got %v", err)
+	}
+}
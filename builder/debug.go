@@ -0,0 +1,202 @@
+package builder
+
+import (
+	"bytes"
+	"database/sql/driver"
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var literalReplacer = strings.NewReplacer(
+	"\\", "\\\\",
+	"'", "''",
+	"\x00", "\\0",
+	"\n", "\\n",
+	"\r", "\\r",
+	"\x1a", "\\Z",
+)
+
+// Interpolate replaces the placeholders of query with args rendered as
+// literals, so a logged query can be pasted into a MySQL client. It is meant
+// for logging only; queries must still be executed with bound arguments.
+func Interpolate(query string, args []interface{}) string {
+	var s strings.Builder
+	next := 0
+	scanSQL(query, func(text string, quoted bool) {
+		if quoted {
+			s.WriteString(text)
+			return
+		}
+		for _, r := range text {
+			if r == '?' && next < len(args) {
+				s.WriteString(literal(args[next]))
+				next++
+				continue
+			}
+			s.WriteRune(r)
+		}
+	})
+	return s.String()
+}
+
+func literal(arg interface{}) string {
+	if valuer, ok := arg.(driver.Valuer); ok {
+		value, err := valuer.Value()
+		if err != nil {
+			return "'" + literalReplacer.Replace(err.Error()) + "'"
+		}
+		arg = value
+	}
+	switch v := arg.(type) {
+	case nil:
+		return "NULL"
+	case string:
+		return "'" + literalReplacer.Replace(v) + "'"
+	case []byte:
+		if v == nil {
+			return "NULL"
+		}
+		return "X'" + hex.EncodeToString(v) + "'"
+	case time.Time:
+		return "'" + v.Format("2006-01-02 15:04:05.999999") + "'"
+	case bool:
+		if v {
+			return "TRUE"
+		}
+		return "FALSE"
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return fmt.Sprint(v)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	}
+	value := reflect.ValueOf(arg)
+	switch value.Kind() {
+	case reflect.Ptr:
+		if value.IsNil() {
+			return "NULL"
+		}
+		return literal(value.Elem().Interface())
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			if value.IsNil() {
+				return "NULL"
+			}
+			return "X'" + hex.EncodeToString(value.Bytes()) + "'"
+		}
+	case reflect.String:
+		return literal(value.String())
+	case reflect.Bool:
+		return literal(value.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(value.Int(), 10)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(value.Uint(), 10)
+	case reflect.Float32, reflect.Float64:
+		return literal(value.Float())
+	}
+	return "'" + literalReplacer.Replace(fmt.Sprint(arg)) + "'"
+}
+
+// String renders the query with its arguments interpolated, for logging.
+// It renders a clone, so logging never affects the builder.
+func (b *builder) String() string {
+	return rebind(b.dialect, Interpolate(b.clone().render()))
+}
+
+var prettyClauses = []string{
+	"ON DUPLICATE KEY UPDATE", "ON CONFLICT", "LEFT JOIN", "RIGHT JOIN", "GROUP BY", "ORDER BY",
+	"UNION ALL", "FOR UPDATE", "FOR SHARE", "UNION", "INTERSECT", "SELECT", "FROM", "JOIN",
+	"WHERE", "HAVING", "WINDOW", "LIMIT", "OFFSET", "SET", "VALUES", "RETURNING",
+}
+
+// Pretty spreads query over several lines, one clause per line with nested
+// subqueries indented, and collapses repeated spaces outside literals.
+func Pretty(query string) string {
+	var out []byte
+	depth := 0
+	scanSQL(strings.TrimSpace(query), func(text string, quoted bool) {
+		if quoted {
+			out = append(out, text...)
+			return
+		}
+		for i := 0; i < len(text); i++ {
+			c := text[i]
+			switch c {
+			case ' ', '\t', '\n':
+				if len(out) > 0 && out[len(out)-1] != ' ' && out[len(out)-1] != '(' {
+					out = append(out, ' ')
+				}
+				continue
+			case '(':
+				depth++
+			case ')':
+				if depth > 0 {
+					depth--
+				}
+			}
+			if len(out) > 0 && (out[len(out)-1] == ' ' || out[len(out)-1] == '(') && startsClause(text[i:]) {
+				out = append(bytes.TrimRight(out, " "), '\n')
+				out = append(out, strings.Repeat("  ", depth)...)
+			}
+			out = append(out, c)
+		}
+	})
+	return string(bytes.TrimRight(out, " "))
+}
+
+func startsClause(text string) bool {
+	for _, clause := range prettyClauses {
+		if !strings.HasPrefix(text, clause) {
+			continue
+		}
+		if len(text) == len(clause) || text[len(clause)] == ' ' || text[len(clause)] == '(' {
+			return true
+		}
+	}
+	return false
+}
+
+// scanSQL splits query into quoted strings and identifiers, passed with
+// quoted set, and the text between them.
+func scanSQL(query string, fn func(text string, quoted bool)) {
+	start := 0
+	for i := 0; i < len(query); i++ {
+		quote := query[i]
+		if quote != '\'' && quote != '"' && quote != '`' {
+			continue
+		}
+		if i > start {
+			fn(query[start:i], false)
+		}
+		end := i + 1
+		for end < len(query) {
+			if query[end] == '\\' && quote != '`' {
+				end += 2
+				continue
+			}
+			if query[end] == quote {
+				if end+1 < len(query) && query[end+1] == quote {
+					end += 2
+					continue
+				}
+				break
+			}
+			end++
+		}
+		if end >= len(query) {
+			end = len(query) - 1
+		}
+		fn(query[i:end+1], true)
+		start = end + 1
+		i = end
+	}
+	if start < len(query) {
+		fn(query[start:], false)
+	}
+}
@@ -0,0 +1,63 @@
+package builder
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+type status string
+
+func TestInterpolate(t *testing.T) {
+	at := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	var missing *int
+	n := 3
+	got := Interpolate(
+		"SELECT '?', `a?` FROM `t` WHERE a = ? AND b = ? AND c = ? AND d = ? AND e = ? AND f = ? AND g = ? AND h = ?",
+		[]interface{}{"it's \\ ok", at, []byte{0xde, 0xad}, nil, true, 1.5, missing, &n},
+	)
+	want := "SELECT '?', `a?` FROM `t` WHERE a = 'it''s \\\\ ok' AND b = '2024-01-02 03:04:05' AND c = X'dead' AND d = NULL AND " +
+		"e = TRUE AND f = 1.5 AND g = NULL AND h = 3"
+	if got != want {
+		t.Fatalf("\n got %s\nwant %s", got, want)
+	}
+	if got := Interpolate("a = ? AND b = ?", []interface{}{1}); got != "a = 1 AND b = ?" {
+		t.Fatalf("got %s", got)
+	}
+	got = Interpolate("a = ? AND b = ? AND c = ?", []interface{}{json.RawMessage(`{"a"}`), status("o'k"), time.Duration(5)})
+	if got != "a = X'7b2261227d' AND b = 'o''k' AND c = 5" {
+		t.Fatalf("got %s", got)
+	}
+
+	b := New().Select("*").From("`post`").Equal("title", "O'Reilly").Size(5)
+	if got := b.String(); got != "SELECT * FROM `post` WHERE `title` = 'O''Reilly' LIMIT 5 " {
+		t.Fatalf("got %q", got)
+	}
+	b = New().Select("*").From("`post`").Allow("post", "id").Equal("title", "a")
+	_ = b.String()
+	if b.(*builder).err != nil {
+		t.Fatalf("String changed the builder: %v", b.(*builder).err)
+	}
+}
+
+func TestPretty(t *testing.T) {
+	query, _ := New().Select("p.*").From("`post`", "p").Join("`user`", "u.`id` = p.`user_id`", "u").
+		InSubquery("p.id", New().Select("`post_id`").From("`tag`").Equal("name", "a  b")).
+		Order(OrderBy{Column: "p.id", Direction: "DESC"}).Page(2).Size(10).Build()
+	want := "SELECT p.*\n" +
+		"FROM `post` p\n" +
+		"JOIN `user` u ON u.`id` = p.`user_id`\n" +
+		"WHERE p.`id` IN (\n" +
+		"  SELECT `post_id`\n" +
+		"  FROM `tag`\n" +
+		"  WHERE `name` = ?)\n" +
+		"ORDER BY p.`id` DESC\n" +
+		"LIMIT 10\n" +
+		"OFFSET 10"
+	if got := Pretty(query); got != want {
+		t.Fatalf("\n got %s\nwant %s", got, want)
+	}
+	if got := Pretty("SELECT 'a  FROM  b' FROM `x`"); got != "SELECT 'a  FROM  b'\nFROM `x`" {
+		t.Fatalf("got %q", got)
+	}
+}
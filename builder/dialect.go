@@ -0,0 +1,111 @@
+package builder
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrUnsupportedDialect = errors.New("statement is not supported by the dialect")
+
+// Dialect selects the SQL flavour of statements whose syntax differs
+// between databases. Identifiers are quoted and placeholders numbered the
+// way the dialect expects; everything else is rendered for MySQL.
+type Dialect int
+
+const (
+	MySQL Dialect = iota
+	PostgreSQL
+	SQLite
+)
+
+func (b *builder) Dialect(dialect Dialect) Builder {
+	b.dialect = dialect
+	return b
+}
+
+// FromSelect inserts the rows selected by other into columns, in the order
+// other selects them.
+func (b *builder) FromSelect(other Builder, columns ...string) Builder {
+	b.insertSelect = other
+	b.columns = columns
+	return b
+}
+
+// Ignore skips rows that would violate a unique key instead of failing.
+func (b *builder) Ignore() Builder {
+	b.insertModifier = "IGNORE"
+	return b
+}
+
+// Replace deletes rows that would violate a unique key before inserting.
+func (b *builder) Replace() Builder {
+	b.insertModifier = "REPLACE"
+	return b
+}
+
+// insertClause renders the insert verb and the clause closing the
+// statement for the chosen modifier and dialect.
+func (b *builder) insertClause() (string, string) {
+	switch b.insertModifier {
+	case "IGNORE":
+		switch b.dialect {
+		case PostgreSQL:
+			return "INSERT INTO ", " ON CONFLICT DO NOTHING"
+		case SQLite:
+			return "INSERT OR IGNORE INTO ", ""
+		}
+		return "INSERT IGNORE INTO ", ""
+	case "REPLACE":
+		return "REPLACE INTO ", ""
+	}
+	return "INSERT INTO ", ""
+}
+
+// embed renders other for nesting inside b, taking over its error. The
+// dialect is left to the outer statement.
+func (b *builder) embed(other Builder) (string, []interface{}) {
+	b.fail(other.Err())
+	return nested(other)
+}
+
+// nested renders other as MySQL so the outer Build rebinds it once.
+func nested(other Builder) (string, []interface{}) {
+	if o, ok := other.(*builder); ok {
+		return o.render()
+	}
+	return other.Build()
+}
+
+// rebind rewrites a statement rendered for MySQL to the identifier quotes
+// and placeholders of dialect. Quoted strings are left as they are.
+func rebind(dialect Dialect, query string) string {
+	if dialect == MySQL {
+		return query
+	}
+	var s strings.Builder
+	n := 0
+	scanSQL(query, func(text string, quoted bool) {
+		switch {
+		case quoted && text[0] == '`':
+			name := strings.TrimSuffix(text[1:], "`")
+			name = strings.ReplaceAll(name, "``", "`")
+			s.WriteString(`"`)
+			s.WriteString(strings.ReplaceAll(name, `"`, `""`))
+			s.WriteString(`"`)
+		case !quoted && dialect == PostgreSQL:
+			for _, r := range text {
+				if r == '?' {
+					n++
+					s.WriteString("$")
+					s.WriteString(strconv.Itoa(n))
+					continue
+				}
+				s.WriteRune(r)
+			}
+		default:
+			s.WriteString(text)
+		}
+	})
+	return s.String()
+}
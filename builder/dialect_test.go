@@ -0,0 +1,76 @@
+package builder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestInsertSelect(t *testing.T) {
+	source := New().Select("`id`,`name`").From("`user`").Equal("active", true)
+	query, values := New().Table("`user_archive`").FromSelect(source, "id", "name").Build()
+	if query != "INSERT INTO `user_archive`(`id`,`name`) SELECT `id`,`name` FROM `user` WHERE `active` = ?" {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{true}) {
+		t.Fatalf("wrong values %v", values)
+	}
+	query, _ = New().Table("`user_archive`").Ignore().FromSelect(source).Build()
+	if query != "INSERT IGNORE INTO `user_archive` SELECT `id`,`name` FROM `user` WHERE `active` = ?" {
+		t.Fatalf("got %q", query)
+	}
+}
+
+func TestInsertModifier(t *testing.T) {
+	data := map[string]interface{}{"id": 1}
+	cases := []struct {
+		dialect  Dialect
+		modifier func(Builder) Builder
+		query    string
+		err      error
+	}{
+		{MySQL, Builder.Ignore, "INSERT IGNORE INTO `t`(`id`) VALUES (?);", nil},
+		{MySQL, Builder.Replace, "REPLACE INTO `t`(`id`) VALUES (?);", nil},
+		{SQLite, Builder.Ignore, `INSERT OR IGNORE INTO "t"("id") VALUES (?);`, nil},
+		{SQLite, Builder.Replace, `REPLACE INTO "t"("id") VALUES (?);`, nil},
+		{PostgreSQL, Builder.Ignore, `INSERT INTO "t"("id") VALUES ($1) ON CONFLICT DO NOTHING;`, nil},
+		{PostgreSQL, Builder.Replace, `REPLACE INTO "t"("id") VALUES ($1);`, ErrUnsupportedDialect},
+	}
+	for _, c := range cases {
+		b := c.modifier(New().Table("`t`").Dialect(c.dialect)).Insert(data)
+		query, _ := b.Build()
+		if query != c.query || b.Err() != c.err {
+			t.Fatalf("got %q %v", query, b.Err())
+		}
+	}
+	batches := New().Table("`t`").Ignore().InsertMany([]map[string]interface{}{data, data})
+	if batches[0].Query != "INSERT IGNORE INTO `t`(`id`) VALUES (?),(?);" {
+		t.Fatalf("got %q", batches[0].Query)
+	}
+}
+
+func TestRebind(t *testing.T) {
+	source := New().Select("`id`").From("`user`").Equal("name", "it's ?").In(map[string]interface{}{"role": []string{"a", "b"}})
+	query, values := New().Table("`user_archive`").Dialect(PostgreSQL).FromSelect(source, "id").Build()
+	if query != `INSERT INTO "user_archive"("id") SELECT "id" FROM "user" WHERE "name" = $1 AND "role" IN ($2,$3) ` {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"it's ?", "a", "b"}) {
+		t.Fatalf("wrong values %v", values)
+	}
+	if got := rebind(PostgreSQL, "SELECT '?', `a``b\"c` FROM t WHERE x = ?"); got != `SELECT '?', "a`+"`"+`b""c" FROM t WHERE x = $1` {
+		t.Fatalf("got %q", got)
+	}
+	batches := New().Table("`t`").Dialect(PostgreSQL).Ignore().InsertMany([]map[string]interface{}{{"id": 1}, {"id": 2}})
+	if batches[0].Query != `INSERT INTO "t"("id") VALUES ($1),($2) ON CONFLICT DO NOTHING;` {
+		t.Fatalf("got %q", batches[0].Query)
+	}
+}
+
+func TestInsertSelectErr(t *testing.T) {
+	source := New().Select("`id`").From("`user`").Equal("1=1 OR id", 1)
+	b := New().Table("`user_archive`").FromSelect(source, "id")
+	if !errors.Is(b.Err(), ErrInvalidIdentifier) {
+		t.Fatalf("got %v", b.Err())
+	}
+}
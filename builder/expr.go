@@ -0,0 +1,109 @@
+package builder
+
+import "strings"
+
+// Expr is a boolean expression rendered by Builder.Where. Conditions are the
+// leaf predicates; And, Or and Not combine them into a tree.
+type Expr interface {
+	expr(b *builder) (string, []interface{})
+}
+
+func (c Condition) expr(b *builder) (string, []interface{}) {
+	stmt, values, err := buildComparison(b.identifier(c.Key), c.Operator, c.Value)
+	if err != nil {
+		b.fail(err)
+	}
+	return stmt, values
+}
+
+// renderExpr renders expr as a top level clause, parenthesized when it
+// combines several operands so it can be joined with other clauses safely.
+func renderExpr(b *builder, expr Expr) (string, []interface{}) {
+	if expr == nil {
+		return "", nil
+	}
+	if tmp, ok := expr.(*group); ok {
+		stmt, values, n := tmp.render(b)
+		if n > 1 {
+			return "(" + stmt + ")", values
+		}
+		return stmt, values
+	}
+	return expr.expr(b)
+}
+
+type group struct {
+	operator string
+	items    []Expr
+}
+
+func And(items ...Expr) Expr {
+	return &group{operator: " AND ", items: items}
+}
+
+func Or(items ...Expr) Expr {
+	return &group{operator: " OR ", items: items}
+}
+
+func (g *group) expr(b *builder) (string, []interface{}) {
+	stmt, values, _ := g.render(b)
+	return stmt, values
+}
+
+// render also reports how many operands were written, so a nested group
+// only gets parentheses when it actually combines something.
+func (g *group) render(b *builder) (string, []interface{}, int) {
+	var stmt strings.Builder
+	var values []interface{}
+	n := 0
+	for _, item := range g.items {
+		if item == nil {
+			continue
+		}
+		var tmp string
+		var args []interface{}
+		compound := false
+		if inner, ok := item.(*group); ok {
+			var count int
+			tmp, args, count = inner.render(b)
+			compound = count > 1
+		} else {
+			tmp, args = item.expr(b)
+		}
+		if len(tmp) == 0 {
+			continue
+		}
+		if n > 0 {
+			stmt.WriteString(g.operator)
+		}
+		if compound {
+			stmt.WriteString("(")
+			stmt.WriteString(tmp)
+			stmt.WriteString(")")
+		} else {
+			stmt.WriteString(tmp)
+		}
+		values = append(values, args...)
+		n++
+	}
+	return stmt.String(), values, n
+}
+
+type not struct {
+	inner Expr
+}
+
+func Not(inner Expr) Expr {
+	return &not{inner: inner}
+}
+
+func (n *not) expr(b *builder) (string, []interface{}) {
+	if n.inner == nil {
+		return "", nil
+	}
+	stmt, values := n.inner.expr(b)
+	if len(stmt) == 0 {
+		return "", nil
+	}
+	return "NOT (" + stmt + ")", values
+}
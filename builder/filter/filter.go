@@ -0,0 +1,429 @@
+// Package filter parses the filters of a REST listing into builder
+// QueryParams, e.g.
+//
+//	?status=in:a,b&created_at=between:2024-01-01,2024-02-01&sort=-created_at&page=2
+//
+// A Schema declares per endpoint which fields may be filtered or sorted on,
+// with which operators and value types. A field given without an operator
+// is compared for equality. Between includes both bounds, except on Time
+// fields where the upper bound is excluded, so the example above matches
+// January and nothing of February 1st.
+package filter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/louvri/gosl/builder"
+)
+
+const (
+	Eq      = "eq"
+	Ne      = "ne"
+	Lt      = "lt"
+	Lte     = "lte"
+	Gt      = "gt"
+	Gte     = "gte"
+	Like    = "like"
+	In      = "in"
+	NotIn   = "nin"
+	Between = "between"
+)
+
+var comparisons = map[string]builder.Operator{
+	Eq:   builder.Eq,
+	Ne:   builder.Ne,
+	Lt:   builder.Lt,
+	Lte:  builder.Lte,
+	Gt:   builder.Gt,
+	Gte:  builder.Gte,
+	Like: builder.Like,
+}
+
+// Type is the type values of a field are parsed into.
+type Type int
+
+const (
+	String Type = iota
+	Int
+	Float
+	Bool
+	Time
+)
+
+// TimeLayouts are the layouts tried, in order, to parse Time values.
+var TimeLayouts = []string{time.RFC3339, builder.DateTimeFormat, "2006-01-02"}
+
+// Field describes a filterable field. Column defaults to the field name and
+// Operators to Eq only.
+type Field struct {
+	Column    string
+	Type      Type
+	Operators []string
+	Sortable  bool
+}
+
+// Schema lists the fields an endpoint accepts. The sort, page and size
+// parameters are reserved.
+type Schema struct {
+	Fields      map[string]Field
+	DefaultSize int
+	MaxSize     int
+}
+
+// FieldError reports why the value of a field was rejected.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// Errors holds every field rejected while parsing.
+type Errors []FieldError
+
+func (e Errors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Request is the JSON form of a listing request:
+//
+//	{"filters": {"status": {"in": ["a", "b"]}, "title": "go"}, "sort": ["-created_at"], "page": 2}
+//
+// A filter given as a plain value is compared for equality.
+type Request struct {
+	Filters map[string]json.RawMessage `json:"filters"`
+	Sort    []string                   `json:"sort"`
+	Page    int                        `json:"page"`
+	Size    int                        `json:"size"`
+}
+
+// ParseQuery parses a raw query string, see Parse.
+func (s Schema) ParseQuery(query string) (builder.QueryParams, error) {
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return builder.QueryParams{}, Errors{{Field: "query", Message: err.Error()}}
+	}
+	return s.Parse(values)
+}
+
+// Parse turns the query parameters of a request into QueryParams. All
+// rejected fields are reported together as Errors. Repeated in and nin
+// filters on a field are merged into one list.
+func (s Schema) Parse(values url.Values) (builder.QueryParams, error) {
+	p := parser{schema: s}
+	for _, key := range sortedKeys(values) {
+		switch key {
+		case "sort":
+			for _, value := range values[key] {
+				p.sort(strings.Split(value, ","))
+			}
+		case "page":
+			p.params.Page = p.number(key, values.Get(key))
+		case "size":
+			p.size(p.number(key, values.Get(key)))
+		default:
+			for _, value := range values[key] {
+				operator, raw := Eq, value
+				if i := strings.Index(value, ":"); i > 0 {
+					if p.operator(value[:i]) {
+						operator, raw = value[:i], value[i+1:]
+					}
+				}
+				var items []string
+				switch operator {
+				case In, NotIn, Between:
+					items = strings.Split(raw, ",")
+				default:
+					items = []string{raw}
+				}
+				p.filter(key, operator, items)
+			}
+		}
+	}
+	return p.result()
+}
+
+// ParseJSON parses the JSON form of a request, see Request.
+func (s Schema) ParseJSON(data []byte) (builder.QueryParams, error) {
+	var request Request
+	if err := json.Unmarshal(data, &request); err != nil {
+		return builder.QueryParams{}, Errors{{Field: "body", Message: err.Error()}}
+	}
+	p := parser{schema: s}
+	keys := make([]string, 0, len(request.Filters))
+	for key := range request.Filters {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if isNull(request.Filters[key]) {
+			p.fail(key, "null is not allowed")
+			continue
+		}
+		var operators map[string]json.RawMessage
+		if err := json.Unmarshal(request.Filters[key], &operators); err != nil {
+			items, err := jsonValues(request.Filters[key])
+			if err != nil {
+				p.fail(key, err.Error())
+				continue
+			}
+			p.filter(key, Eq, items)
+			continue
+		}
+		names := make([]string, 0, len(operators))
+		for name := range operators {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if isNull(operators[name]) {
+				p.fail(key, "null is not allowed")
+				continue
+			}
+			items, err := jsonValues(operators[name])
+			if err != nil {
+				p.fail(key, err.Error())
+				continue
+			}
+			p.filter(key, name, items)
+		}
+	}
+	p.sort(request.Sort)
+	p.params.Page = request.Page
+	p.size(request.Size)
+	return p.result()
+}
+
+func isNull(data json.RawMessage) bool {
+	return strings.TrimSpace(string(data)) == "null"
+}
+
+// jsonValues flattens a JSON scalar or array into the strings Parse works on.
+func jsonValues(data json.RawMessage) ([]string, error) {
+	var items []interface{}
+	if err := json.Unmarshal(data, &items); err != nil {
+		var item interface{}
+		if err := json.Unmarshal(data, &item); err != nil {
+			return nil, err
+		}
+		items = []interface{}{item}
+	}
+	values := make([]string, len(items))
+	for i, item := range items {
+		switch v := item.(type) {
+		case string:
+			values[i] = v
+		case float64:
+			values[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			values[i] = strconv.FormatBool(v)
+		case nil:
+			return nil, errors.New("null is not allowed")
+		default:
+			return nil, fmt.Errorf("unsupported value %v", item)
+		}
+	}
+	return values, nil
+}
+
+type parser struct {
+	schema Schema
+	params builder.QueryParams
+	errors Errors
+}
+
+func (p *parser) fail(field, message string) {
+	p.errors = append(p.errors, FieldError{Field: field, Message: message})
+}
+
+func (p *parser) result() (builder.QueryParams, error) {
+	if len(p.errors) > 0 {
+		return builder.QueryParams{}, p.errors
+	}
+	if p.params.Size == 0 {
+		p.params.Size = p.schema.DefaultSize
+	}
+	return p.params, nil
+}
+
+func (p *parser) operator(name string) bool {
+	switch name {
+	case In, NotIn, Between:
+		return true
+	}
+	_, ok := comparisons[name]
+	return ok
+}
+
+func (p *parser) filter(key, operator string, items []string) {
+	field, ok := p.schema.Fields[key]
+	if !ok {
+		p.fail(key, "unknown field")
+		return
+	}
+	if !p.operator(operator) {
+		p.fail(key, fmt.Sprintf("unknown operator %q", operator))
+		return
+	}
+	if !field.allows(operator) {
+		p.fail(key, fmt.Sprintf("operator %q is not allowed", operator))
+		return
+	}
+	values := make([]interface{}, len(items))
+	for i, item := range items {
+		value, err := field.parse(item)
+		if err != nil {
+			p.fail(key, err.Error())
+			return
+		}
+		values[i] = value
+	}
+	column := field.Column
+	if column == "" {
+		column = key
+	}
+	switch operator {
+	case In, NotIn:
+		target := &p.params.In
+		if operator == NotIn {
+			target = &p.params.Notin
+		}
+		if *target == nil {
+			*target = make(map[string]interface{})
+		}
+		if previous, ok := (*target)[column].([]interface{}); ok {
+			values = append(previous, values...)
+		}
+		(*target)[column] = values
+	case Between:
+		if len(values) != 2 {
+			p.fail(key, "between takes two values")
+			return
+		}
+		upper := builder.Lte
+		if field.Type == Time {
+			upper = builder.Lt
+		}
+		p.params.Conditions = append(p.params.Conditions,
+			builder.Condition{Key: column, Operator: builder.Gte, Value: values[0]},
+			builder.Condition{Key: column, Operator: upper, Value: values[1]},
+		)
+	default:
+		if len(values) != 1 {
+			p.fail(key, fmt.Sprintf("%s takes one value", operator))
+			return
+		}
+		p.params.Conditions = append(p.params.Conditions, builder.Condition{
+			Key:      column,
+			Operator: comparisons[operator],
+			Value:    values[0],
+		})
+	}
+}
+
+func (p *parser) sort(items []string) {
+	for _, item := range items {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		direction := "ASC"
+		if strings.HasPrefix(item, "-") {
+			direction, item = "DESC", item[1:]
+		} else {
+			item = strings.TrimPrefix(item, "+")
+		}
+		field, ok := p.schema.Fields[item]
+		if !ok || !field.Sortable {
+			p.fail("sort", fmt.Sprintf("cannot sort on %q", item))
+			continue
+		}
+		column := field.Column
+		if column == "" {
+			column = item
+		}
+		p.params.Orderby = append(p.params.Orderby, builder.OrderBy{Column: column, Direction: direction})
+	}
+}
+
+func (p *parser) number(key, value string) int {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		p.fail(key, "must be a positive integer")
+		return 0
+	}
+	return n
+}
+
+func (p *parser) size(n int) {
+	if p.schema.MaxSize > 0 && n > p.schema.MaxSize {
+		p.fail("size", fmt.Sprintf("must not exceed %d", p.schema.MaxSize))
+		return
+	}
+	p.params.Size = n
+}
+
+func (f Field) allows(operator string) bool {
+	if len(f.Operators) == 0 {
+		return operator == Eq
+	}
+	for _, item := range f.Operators {
+		if item == operator {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Field) parse(value string) (interface{}, error) {
+	switch f.Type {
+	case Int:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not an integer", value)
+		}
+		return n, nil
+	case Float:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number", value)
+		}
+		return n, nil
+	case Bool:
+		v, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a boolean", value)
+		}
+		return v, nil
+	case Time:
+		for _, layout := range TimeLayouts {
+			if t, err := time.Parse(layout, value); err == nil {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("%q is not a time", value)
+	}
+	return value, nil
+}
+
+func sortedKeys(values url.Values) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
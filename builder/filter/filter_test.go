@@ -0,0 +1,116 @@
+package filter
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/louvri/gosl/builder"
+)
+
+var schema = Schema{
+	Fields: map[string]Field{
+		"status":     {Operators: []string{Eq, In, NotIn}},
+		"price":      {Type: Float, Operators: []string{Gte, Lt, Between}},
+		"created_at": {Type: Time, Operators: []string{Between}, Sortable: true},
+		"author":     {Column: "user_id", Type: Int},
+		"title":      {Operators: []string{Like}, Sortable: true},
+	},
+	DefaultSize: 20,
+	MaxSize:     100,
+}
+
+func TestParseQuery(t *testing.T) {
+	p, err := schema.ParseQuery("status=in:a,b&created_at=between:2024-01-01,2024-02-01&price=gte:9.5&author=7&sort=-created_at,title&page=2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	query, values := builder.FromParams("`post`", p).Build()
+	expected := "SELECT * FROM `post` WHERE `status` IN (?,?)  AND `user_id` = ? AND `created_at` >= ? AND `created_at` < ? AND `price` >= ? ORDER BY `created_at` DESC,`title` ASC LIMIT 20  OFFSET 20 "
+	if query != expected {
+		t.Fatalf("query:\n got %q\nwant %q", query, expected)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"a", "b", int64(7), time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), 9.5}) {
+		t.Fatalf("values: got %#v", values)
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	p, err := schema.ParseJSON([]byte(`{
+		"filters": {"status": {"nin": ["draft"]}, "price": {"between": [1, 2]}, "title": {"like": "%go%"}},
+		"sort": ["title"],
+		"size": 5
+	}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := builder.QueryParams{
+		Notin: map[string]interface{}{"status": []interface{}{"draft"}},
+		Conditions: []builder.Condition{
+			{Key: "price", Operator: builder.Gte, Value: 1.0},
+			{Key: "price", Operator: builder.Lte, Value: 2.0},
+			{Key: "title", Operator: builder.Like, Value: "%go%"},
+		},
+		Orderby: []builder.OrderBy{{Column: "title", Direction: "ASC"}},
+		Size:    5,
+	}
+	if !reflect.DeepEqual(p, expected) {
+		t.Fatalf("got %#v", p)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	_, err := schema.ParseQuery("secret=1&status=gt:1&author=x&created_at=between:2024-01-01&sort=price&size=500")
+	var errs Errors
+	if !errors.As(err, &errs) {
+		t.Fatalf("got %v", err)
+	}
+	expected := Errors{
+		{Field: "author", Message: `"x" is not an integer`},
+		{Field: "created_at", Message: "between takes two values"},
+		{Field: "secret", Message: "unknown field"},
+		{Field: "size", Message: "must not exceed 100"},
+		{Field: "sort", Message: `cannot sort on "price"`},
+		{Field: "status", Message: `operator "gt" is not allowed`},
+	}
+	if !reflect.DeepEqual(errs, expected) {
+		t.Fatalf("got %#v", errs)
+	}
+	if _, err := schema.ParseQuery("created_at=between:2024-01-01,2024-02-01"); err != nil {
+		t.Fatal(err)
+	}
+	p, _ := schema.ParseQuery("created_at=between:2024-01-01T10:00:00Z,2024-01-02")
+	if !p.Conditions[0].Value.(time.Time).Equal(time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Fatalf("got %v", p.Conditions)
+	}
+}
+
+func TestParseRepeated(t *testing.T) {
+	p, err := schema.ParseQuery("status=in:a,b&status=in:c&status=nin:x&status=nin:y")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(p.In["status"], []interface{}{"a", "b", "c"}) || !reflect.DeepEqual(p.Notin["status"], []interface{}{"x", "y"}) {
+		t.Fatalf("got %#v %#v", p.In, p.Notin)
+	}
+	p, err = schema.ParseQuery("created_at=between:2024-01-01,2024-02-01&created_at=between:2024-01-15,2024-03-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Conditions) != 4 {
+		t.Fatalf("got %#v", p.Conditions)
+	}
+}
+
+func TestParseJSONNull(t *testing.T) {
+	_, err := schema.ParseJSON([]byte(`{"filters": {"author": null, "status": {"in": null}, "title": {"like": [null]}}}`))
+	expected := Errors{
+		{Field: "author", Message: "null is not allowed"},
+		{Field: "status", Message: "null is not allowed"},
+		{Field: "title", Message: "null is not allowed"},
+	}
+	if !reflect.DeepEqual(err, expected) {
+		t.Fatalf("got %#v", err)
+	}
+}
@@ -1,12 +1,28 @@
 package builder
 
 import (
+	"fmt"
 	"reflect"
-	"regexp"
+	"sort"
 	"strings"
 )
 
+// sortedKeys gives map driven statements a stable column order, keeping the
+// rendered SQL identical between runs.
+func sortedKeys(data map[string]interface{}) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
 func buildInStatement(prop string, data interface{}) (string, []interface{}) {
+	return buildMembershipStatement(prop, "IN", data)
+}
+
+func buildMembershipStatement(prop, operator string, data interface{}) (string, []interface{}) {
 	var s strings.Builder
 	var values []interface{}
 	reflectItems := reflect.ValueOf(data)
@@ -14,14 +30,10 @@ func buildInStatement(prop string, data interface{}) (string, []interface{}) {
 	if n <= 0 {
 		return "", nil
 	}
-	if !strings.Contains(prop, "`") {
-		s.WriteString("`")
-		s.WriteString(prop)
-		s.WriteString("`")
-	} else {
-		s.WriteString(prop)
-	}
-	s.WriteString(" IN (")
+	s.WriteString(quoteKey(prop))
+	s.WriteString(" ")
+	s.WriteString(operator)
+	s.WriteString(" (")
 	for i := 0; i < n; i++ {
 		if i > 0 {
 			s.WriteString(",")
@@ -33,84 +45,139 @@ func buildInStatement(prop string, data interface{}) (string, []interface{}) {
 	return s.String(), values
 }
 
-func buildConditionStatement(condition Condition) (string, interface{}) {
+// buildComparison renders key compared to value with operator. A comparison
+// that fails validation renders as FALSE along with the error, so a query
+// executed regardless matches nothing.
+func buildComparison(key string, operator Operator, value interface{}) (string, []interface{}, error) {
+	operator, err := ParseOperator(string(operator))
+	if err != nil {
+		return "FALSE", nil, err
+	}
+	if value == nil {
+		switch operator {
+		case Eq:
+			operator = IsNull
+		case Ne:
+			operator = IsNotNull
+		}
+	}
+	if err := operator.validate(value); err != nil {
+		return "FALSE", nil, err
+	}
 	var s strings.Builder
-	if !strings.Contains(condition.Key, "`") && !strings.Contains(condition.Key, "'$.") {
-		tokens := strings.Split(condition.Key, ".")
-		if len(tokens) == 2 {
-			s.WriteString(tokens[0])
-			s.WriteString(".")
-			s.WriteString("`")
-			s.WriteString(tokens[1])
-			s.WriteString("`")
+	var values []interface{}
+	switch operator {
+	case IsNull, IsNotNull:
+		s.WriteString(key)
+		s.WriteString(" ")
+		s.WriteString(string(operator))
+	case Between:
+		items := reflect.ValueOf(value)
+		s.WriteString(key)
+		s.WriteString(" BETWEEN ? AND ?")
+		values = append(values, items.Index(0).Interface(), items.Index(1).Interface())
+	case In, NotIn:
+		items := reflect.ValueOf(value)
+		if items.Len() == 0 {
+			if operator == In {
+				return "FALSE", nil, nil
+			}
+			return "TRUE", nil, nil
+		}
+		s.WriteString(key)
+		s.WriteString(" ")
+		s.WriteString(string(operator))
+		s.WriteString(" (")
+		for i := 0; i < items.Len(); i++ {
+			if i > 0 {
+				s.WriteString(",")
+			}
+			s.WriteString("?")
+			values = append(values, items.Index(i).Interface())
+		}
+		s.WriteString(")")
+	case JSONContains:
+		s.WriteString("JSON_CONTAINS(")
+		s.WriteString(key)
+		s.WriteString(", ")
+		if _, ok := rawValue(value); ok {
+			values = writeValue(&s, value)
 		} else {
-			s.WriteString("`")
-			s.WriteString(condition.Key)
-			s.WriteString("` ")
+			document, err := jsonDocument(value)
+			if err != nil {
+				return "FALSE", nil, fmt.Errorf("%w %s: %v", ErrInvalidValue, operator, err)
+			}
+			s.WriteString("?")
+			values = append(values, document)
 		}
-	} else {
-		s.WriteString(condition.Key)
+		s.WriteString(")")
+	default:
+		s.WriteString(key)
+		s.WriteString(" ")
+		s.WriteString(string(operator))
+		s.WriteString(" ")
+		values = writeValue(&s, value)
 	}
+	return s.String(), values, nil
+}
 
-	s.WriteString(condition.Operator)
-	s.WriteString(" ")
-	if condition.Value == nil || condition.Value == "null" {
-		s.WriteString("null")
-		return s.String(), nil
-	} else if tmp, ok := condition.Value.(string); ok && strings.Contains(tmp, "`") {
-		s.WriteString(tmp)
-		return s.String(), nil
-	} else {
-		s.WriteString("?")
-		return s.String(), condition.Value
+func buildOrder(order OrderBy) (string, []interface{}, error) {
+	var s strings.Builder
+	order.Column = quoteKey(order.Column)
+	if len(order.Fields) > 0 {
+		values := make([]interface{}, len(order.Fields))
+		s.WriteString("Field(")
+		s.WriteString(order.Column)
+		for i, field := range order.Fields {
+			s.WriteString(",?")
+			values[i] = field
+		}
+		s.WriteString(")")
+		return s.String(), values, nil
+	}
+	direction := strings.ToUpper(order.Direction)
+	if direction != "" && direction != "ASC" && direction != "DESC" {
+		return order.Column, nil, fmt.Errorf("%w: %q", ErrInvalidDirection, order.Direction)
 	}
+	s.WriteString(order.Column)
+	s.WriteString(" ")
+	s.WriteString(direction)
+	return s.String(), nil, nil
 }
 
-func buildInsert(table string, data map[string]interface{}, columns []string) (string, []interface{}) {
+func buildInsert(verb string, table string, data map[string]interface{}, columns []string, suffix string) (string, []interface{}) {
 	var query strings.Builder
 	var fields strings.Builder
 	var placeholder strings.Builder
 	values := make([]interface{}, 0)
 	build := func(key string, value interface{}) {
-		if !strings.Contains(key, "`") {
-			key = "`" + key + "`"
-		}
-		if value != nil {
-			if fields.Len() > 0 {
-				fields.WriteString(",")
-				placeholder.WriteString(",")
-			}
-			fields.WriteString(key)
-			placeholder.WriteString("?")
-			values = append(values, value)
-		} else {
-			if fields.Len() > 0 {
-				fields.WriteString(",")
-				placeholder.WriteString(",")
-			}
-			placeholder.WriteString("NULL")
+		if fields.Len() > 0 {
+			fields.WriteString(",")
+			placeholder.WriteString(",")
 		}
+		fields.WriteString(quoteKey(key))
+		values = append(values, writeValue(&placeholder, value)...)
 	}
 	if len(columns) > 0 {
 		for _, column := range columns {
-			key := column
-			value := data[column]
-			if value != nil {
-				build(key, value)
+			if value := data[column]; value != nil {
+				build(column, value)
 			}
 		}
 	} else {
-		for key, value := range data {
-			build(key, value)
+		for _, key := range sortedKeys(data) {
+			build(key, data[key])
 		}
 	}
-	query.WriteString("INSERT INTO ")
+	query.WriteString(verb)
 	query.WriteString(table)
 	query.WriteString("(")
 	query.WriteString(fields.String())
 	query.WriteString(") VALUES (")
 	query.WriteString(placeholder.String())
-	query.WriteString(");")
+	query.WriteString(")")
+	query.WriteString(suffix)
+	query.WriteString(";")
 	return query.String(), values
 }
 
@@ -119,47 +186,23 @@ func buildUpdate(table string, data map[string]interface{}, columns []string) (s
 	var placeholder strings.Builder
 	values := make([]interface{}, 0)
 	build := func(key string, value interface{}) {
-		if !strings.Contains(key, "`") {
-			key = "`" + key + "`"
-		}
-		if value != nil {
-			if placeholder.Len() > 0 {
-				placeholder.WriteString(",")
-			}
-			isAStatement := false
-			if tmp, ok := value.(string); ok {
-				isAStatement = strings.Contains(tmp, "`")
-			}
-			if isAStatement {
-				placeholder.WriteString(key)
-				placeholder.WriteString("=")
-				placeholder.WriteString(value.(string))
-			} else {
-				placeholder.WriteString(key)
-				placeholder.WriteString("=")
-				placeholder.WriteString("?")
-				values = append(values, value)
-			}
-		} else {
-			if placeholder.Len() > 0 {
-				placeholder.WriteString(",")
-			}
-			placeholder.WriteString(key)
-			placeholder.WriteString("=")
-			placeholder.WriteString("NULL")
+		key = quoteKey(key)
+		if placeholder.Len() > 0 {
+			placeholder.WriteString(",")
 		}
+		placeholder.WriteString(key)
+		placeholder.WriteString("=")
+		values = append(values, writeValue(&placeholder, value)...)
 	}
 	if len(columns) > 0 {
 		for _, column := range columns {
-			key := column
-			value := data[column]
-			if value != nil {
-				build(key, value)
+			if value := data[column]; value != nil {
+				build(column, value)
 			}
 		}
 	} else {
-		for key, value := range data {
-			build(key, value)
+		for _, key := range sortedKeys(data) {
+			build(key, data[key])
 		}
 	}
 	query.WriteString("UPDATE ")
@@ -169,108 +212,49 @@ func buildUpdate(table string, data map[string]interface{}, columns []string) (s
 	return query.String(), values
 }
 
-func buildUpsert(table string, data map[string]interface{}, columns []string) (string, []interface{}) {
+func buildUpsert(table string, data map[string]interface{}, columns []string, strategy conflict) (string, []interface{}, error) {
 	var query strings.Builder
 	var fields strings.Builder
 	var insert strings.Builder
-	var update strings.Builder
 	insertValues := make([]interface{}, 0)
-	updateValues := make([]interface{}, 0)
-	buildInsert := func(key string, value interface{}) {
-		if !strings.Contains(key, "`") {
-			key = "`" + key + "`"
-		}
-		if value != nil {
-			if str, ok := value.(string); !ok || ok && !strings.Contains(str, "`") {
-				if fields.Len() > 0 {
-					fields.WriteString(",")
-					insert.WriteString(",")
-				}
-				fields.WriteString(key)
-				insert.WriteString("?")
-				insertValues = append(insertValues, value)
-			} else {
-				strValue := value.(string)
-				if fields.Len() > 0 {
-					fields.WriteString(",")
-					insert.WriteString(",")
-				}
-				fields.WriteString(key)
-				number, _ := regexp.Compile(`-?\d+(\.\d+)?`)
-				numbers := number.FindAllString(strings.TrimSpace(strValue), -1)
-				if len(numbers) > 0 {
-					insert.WriteString("?")
-					insertValues = append(insertValues, strings.Join(numbers, ""))
-				} else {
-					insert.WriteString(strValue)
-				}
-			}
-		} else {
-			if fields.Len() > 0 {
-				fields.WriteString(",")
-				insert.WriteString(",")
-			}
-			fields.WriteString(key)
-			insert.WriteString("NULL")
-		}
-	}
-	buildUpdate := func(key string, value interface{}) {
-		if !strings.Contains(key, "`") {
-			key = "`" + key + "`"
+	keys := make([]string, 0)
+	raws := make(map[string]RawExpr)
+	build := func(key string, value interface{}) {
+		key = quoteKey(key)
+		if fields.Len() > 0 {
+			fields.WriteString(",")
+			insert.WriteString(",")
 		}
-		if value != nil {
-			if update.Len() > 0 {
-				update.WriteString(",")
-			}
-			isAStatement := false
-			if tmp, ok := value.(string); ok {
-				isAStatement = strings.Contains(tmp, "`")
-			}
-			if isAStatement {
-				update.WriteString(key)
-				update.WriteString("=")
-				update.WriteString(value.(string))
-			} else {
-				update.WriteString(key)
-				update.WriteString("=")
-				update.WriteString("?")
-				updateValues = append(updateValues, value)
-			}
-		} else {
-			if update.Len() > 0 {
-				update.WriteString(",")
-			}
-			update.WriteString(key)
-			update.WriteString("=")
-			update.WriteString("NULL")
+		fields.WriteString(key)
+		keys = append(keys, key)
+		if raw, ok := rawValue(value); ok {
+			raws[key] = raw
 		}
+		insertValues = append(insertValues, writeValue(&insert, value)...)
 	}
 	if len(columns) > 0 {
 		for _, column := range columns {
-			key := column
-			value := data[column]
-			if value != nil {
-				buildInsert(key, value)
-				buildUpdate(key, value)
+			if value := data[column]; value != nil {
+				build(column, value)
 			}
 		}
 	} else {
-		for key, value := range data {
-			buildInsert(key, value)
-			buildUpdate(key, value)
+		for _, key := range sortedKeys(data) {
+			build(key, data[key])
 		}
 	}
+	clause, updateValues, err := buildConflictClause(strategy, keys, raws)
 	query.WriteString("INSERT INTO ")
 	query.WriteString(table)
 	query.WriteString("(")
 	query.WriteString(fields.String())
 	query.WriteString(") VALUES (")
 	query.WriteString(insert.String())
-	query.WriteString(") ON DUPLICATE KEY UPDATE ")
-	query.WriteString(update.String())
+	query.WriteString(")")
+	query.WriteString(clause)
 	query.WriteString(";")
 	output := make([]interface{}, 0)
 	output = append(output, insertValues...)
 	output = append(output, updateValues...)
-	return query.String(), output
+	return query.String(), output, err
 }
@@ -0,0 +1,226 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrInvalidIdentifier is reported for a column name that is not a plain,
+// possibly qualified, identifier. Expressions such as DATE(created_at) go
+// through Raw instead.
+var ErrInvalidIdentifier = errors.New("invalid identifier")
+
+var (
+	simpleIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_$]*$`)
+	// unquotedIdentifier holds the characters MySQL allows in an identifier
+	// left unquoted.
+	unquotedIdentifier = regexp.MustCompile(`^[0-9A-Za-z$_\x{0080}-\x{FFFF}]+$`)
+)
+
+// IdentifierError reports a column missing from the allow-list of its table.
+type IdentifierError struct {
+	Table  string
+	Column string
+}
+
+func (e *IdentifierError) Error() string {
+	if e.Table == "" {
+		return fmt.Sprintf("column %q is not allowed", e.Column)
+	}
+	return fmt.Sprintf("column %q is not allowed on table %q", e.Column, e.Table)
+}
+
+// quoteKey quotes a possibly qualified identifier such as schema.table.col.
+// Parts already quoted are kept, simple qualifiers stay bare and anything
+// else is quoted with its backticks doubled, so a name like "id` DESC" can
+// never close the identifier early.
+func quoteKey(key string) string {
+	if i := strings.Index(key, "->"); i > 0 && inlineJSONPath(key[i:]) {
+		return quoteKey(key[:i]) + key[i:]
+	}
+	parts, ok := splitIdentifier(key)
+	if !ok {
+		return "`" + strings.ReplaceAll(key, "`", "``") + "`"
+	}
+	last := len(parts) - 1
+	for i, part := range parts {
+		switch {
+		case strings.HasPrefix(part, "`"):
+		case i == last && part == "*":
+		case i < last && simpleIdentifier.MatchString(part):
+		default:
+			parts[i] = "`" + part + "`"
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// splitIdentifier splits key on the dots outside quoted parts. It fails on
+// empty parts, stray backticks and unterminated quotes.
+func splitIdentifier(key string) ([]string, bool) {
+	parts := make([]string, 0, 3)
+	for {
+		var part string
+		if strings.HasPrefix(key, "`") {
+			end := 1
+			for {
+				i := strings.IndexByte(key[end:], '`')
+				if i < 0 {
+					return nil, false
+				}
+				end += i + 1
+				if end < len(key) && key[end] == '`' {
+					end++
+					continue
+				}
+				break
+			}
+			part, key = key[:end], key[end:]
+		} else {
+			i := strings.IndexByte(key, '.')
+			if i < 0 {
+				i = len(key)
+			}
+			part, key = key[:i], key[i:]
+			if part == "" || strings.Contains(part, "`") {
+				return nil, false
+			}
+		}
+		parts = append(parts, part)
+		if key == "" {
+			return parts, true
+		}
+		if key[0] != '.' {
+			return nil, false
+		}
+		key = key[1:]
+	}
+}
+
+// unquote returns the bare name of the last part of identifier.
+func unquote(identifier string) string {
+	if i := strings.Index(identifier, "->"); i > 0 {
+		identifier = identifier[:i]
+	}
+	parts, ok := splitIdentifier(identifier)
+	if !ok {
+		return identifier
+	}
+	name := parts[len(parts)-1]
+	if strings.HasPrefix(name, "`") {
+		name = strings.ReplaceAll(name[1:len(name)-1], "``", "`")
+	}
+	return name
+}
+
+// Allow restricts the columns the query may refer to on table. Once any
+// table has an allow-list, Build fails with an IdentifierError on columns
+// that are not listed, or that belong to a table without one.
+func (b *builder) Allow(table string, columns ...string) Builder {
+	if b.allow == nil {
+		b.allow = make(map[string]map[string]bool)
+	}
+	allowed := b.allow[unquote(table)]
+	if allowed == nil {
+		allowed = make(map[string]bool)
+		b.allow[unquote(table)] = allowed
+	}
+	for _, column := range columns {
+		allowed[unquote(column)] = true
+	}
+	return b
+}
+
+// validIdentifier reports whether key names a column: identifier parts that
+// are either quoted or valid unquoted, optionally followed by an inline JSON
+// path.
+func validIdentifier(key string) bool {
+	if i := strings.Index(key, "->"); i > 0 && inlineJSONPath(key[i:]) {
+		key = key[:i]
+	}
+	parts, ok := splitIdentifier(key)
+	if !ok {
+		return false
+	}
+	for i, part := range parts {
+		if strings.HasPrefix(part, "`") || i == len(parts)-1 && part == "*" {
+			continue
+		}
+		if !unquotedIdentifier.MatchString(part) {
+			return false
+		}
+	}
+	return true
+}
+
+// identifier quotes a column given to the builder and remembers it for the
+// allow-list check. Names that are not identifiers fail the builder.
+func (b *builder) identifier(name string) string {
+	if !validIdentifier(name) {
+		b.fail(fmt.Errorf("%w: %q", ErrInvalidIdentifier, name))
+	}
+	b.identifiers = append(b.identifiers, name)
+	return quoteKey(name)
+}
+
+// checkIdentifiers validates columns an expression quotes on its own, such
+// as those of aggregates, windows and JSON, the way identifier does.
+func (b *builder) checkIdentifiers(names ...string) {
+	for _, name := range names {
+		b.identifier(name)
+	}
+}
+
+func (b *builder) identifiersErr() error {
+	if len(b.allow) == 0 {
+		return nil
+	}
+	names := append([]string{}, b.identifiers...)
+	for _, data := range []map[string]interface{}{b.insert, b.update, b.upsert} {
+		names = append(names, sortedKeys(data)...)
+	}
+	names = append(names, b.columns...)
+	for _, name := range names {
+		if err := b.allowed(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (b *builder) allowed(name string) error {
+	if i := strings.Index(name, "->"); i > 0 {
+		name = name[:i]
+	}
+	parts, ok := splitIdentifier(name)
+	if !ok {
+		return &IdentifierError{Column: name}
+	}
+	column := unquote(parts[len(parts)-1])
+	if column == "*" {
+		return nil
+	}
+	if len(parts) == 1 {
+		for _, source := range b.source {
+			if b.allow[unquote(source["table"])][column] {
+				return nil
+			}
+		}
+		return &IdentifierError{Column: column}
+	}
+	table := unquote(parts[len(parts)-2])
+	for _, source := range b.source {
+		if source["alias"] != "" && unquote(source["alias"]) == table {
+			if !strings.HasPrefix(source["table"], "(") {
+				table = unquote(source["table"])
+			}
+			break
+		}
+	}
+	if !b.allow[table][column] {
+		return &IdentifierError{Table: table, Column: column}
+	}
+	return nil
+}
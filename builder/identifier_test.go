@@ -0,0 +1,97 @@
+package builder
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQuoteKey(t *testing.T) {
+	cases := map[string]string{
+		"id":                     "`id`",
+		"`id`":                   "`id`",
+		"p.id":                   "p.`id`",
+		"shop.post.id":           "shop.post.`id`",
+		"`shop`.`post`.`id`":     "`shop`.`post`.`id`",
+		"p.*":                    "p.*",
+		"id` DESC; DROP":         "`id`` DESC; DROP`",
+		"`id`` DESC; DROP`":      "`id`` DESC; DROP`",
+		"`id` DESC":              "```id`` DESC`",
+		"my table.id":            "`my table`.`id`",
+		"data->>'$.name'":        "`data`->>'$.name'",
+		"data->>'$.a' OR 1=1 --": "`data->>'$`.`a' OR 1=1 --`",
+	}
+	for key, want := range cases {
+		if got := quoteKey(key); got != want {
+			t.Errorf("quoteKey(%q) = %q, want %q", key, got, want)
+		}
+	}
+	if got := ResolveColumnName("p.userId"); got != "p.`user_id`" {
+		t.Errorf("got %q", got)
+	}
+	if got := ResolveColumnName("id` DESC; DROP"); got != "`id`` DESC; DROP`" {
+		t.Errorf("got %q", got)
+	}
+}
+
+func TestAllow(t *testing.T) {
+	b := New().Select("*").From("`post`", "p").Join("`user`", "u.`id` = p.`user_id`", "u").
+		Allow("post", "id", "title").
+		Allow("user", "name").
+		Equal("p.title", "go").
+		Equal("u.name", "ann").
+		Order(OrderBy{Column: "id", Direction: "DESC"})
+	query, _ := b.Build()
+	if err := b.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if query != "SELECT * FROM `post` p JOIN `user` u  ON u.`id` = p.`user_id` WHERE p.`title` = ? AND u.`name` = ? ORDER BY `id` DESC" {
+		t.Fatalf("got %q", query)
+	}
+
+	b = New().Select("*").From("`post`").Allow("post", "id").Order(OrderBy{Column: "secret", Direction: "ASC"})
+	var identifierErr *IdentifierError
+	if !errors.As(b.Err(), &identifierErr) || identifierErr.Column != "secret" || identifierErr.Table != "" {
+		t.Fatalf("got %v", b.Err())
+	}
+
+	b = New().Select("*").From("`post`").Order(OrderBy{Column: "id` DESC; DROP", Direction: "ASC"})
+	if !errors.Is(b.Err(), ErrInvalidIdentifier) {
+		t.Fatalf("got %v", b.Err())
+	}
+
+	b = New().Table("`post`").Allow("post", "title").Update(map[string]interface{}{"title": "a", "owner_id": 2}).Equal("id", 1)
+	b.Build()
+	if !errors.As(b.Err(), &identifierErr) || identifierErr.Column != "id" {
+		t.Fatalf("got %v", b.Err())
+	}
+}
+
+func TestAllowExpressions(t *testing.T) {
+	inner := New().Select("1").From("`comment`")
+	cases := map[string]func(Builder) Builder{
+		"exists": func(b Builder) Builder {
+			return b.NotExists(inner, Condition{Key: "secret", Operator: "=", Value: Raw("`comment`.`post_id`")})
+		},
+		"aggregate": func(b Builder) Builder { return b.SelectAggregate(Sum("secret")) },
+		"having":    func(b Builder) Builder { return b.Having(Max("secret").Compare(Gt, 1)) },
+		"window": func(b Builder) Builder {
+			return b.SelectWindow(RowNumber().Over(Window{PartitionBy: []string{"secret"}}))
+		},
+		"named window":    func(b Builder) Builder { return b.Window("w", Window{OrderBy: []OrderBy{{Column: "secret"}}}) },
+		"lag":             func(b Builder) Builder { return b.SelectWindow(Lag("secret", 1).OverWindow("w")) },
+		"json column":     func(b Builder) Builder { return b.Where(JSONColumn("secret").Contains(1)) },
+		"json extract":    func(b Builder) Builder { return b.SelectJSON(JSONExtract("secret", "$.a"), "a") },
+		"json comparison": func(b Builder) Builder { return b.Where(JSONPath("secret", "$.a").Compare(Eq, 1)) },
+	}
+	for name, apply := range cases {
+		b := apply(New().Select("*").From("`post`").Allow("post", "id"))
+		var identifierErr *IdentifierError
+		if !errors.As(b.Err(), &identifierErr) || identifierErr.Column != "secret" {
+			t.Fatalf("%s: got %v", name, b.Err())
+		}
+	}
+	b := New().Select("*").From("`post`").SelectAggregate(Count("id` + 1"))
+	if !errors.Is(b.Err(), ErrInvalidIdentifier) {
+		t.Fatalf("got %v", b.Err())
+	}
+}
@@ -0,0 +1,228 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+)
+
+var ErrInvalidJSONPath = errors.New("invalid JSON path")
+
+var jsonPathPattern = regexp.MustCompile(`^\$(\.([A-Za-z_][A-Za-z0-9_]*|\*|"[^"\\']*")|\[([0-9]+|\*|last)\]|\*\*)*$`)
+
+func validateJSONPath(path string) error {
+	if !jsonPathPattern.MatchString(path) {
+		return fmt.Errorf("%w: %q", ErrInvalidJSONPath, path)
+	}
+	return nil
+}
+
+// inlineJSONPath reports whether s is a ->'$.path' or ->>'$.path' suffix
+// with a valid path, the only form a key may inline a path in.
+func inlineJSONPath(s string) bool {
+	s = strings.TrimPrefix(strings.TrimPrefix(s, "->"), ">")
+	return len(s) >= 2 && s[0] == '\'' && s[len(s)-1] == '\'' && validateJSONPath(s[1:len(s)-1]) == nil
+}
+
+// JSON is an expression over a JSON column. Paths are validated and, except
+// for JSONPath, bound as parameters.
+type JSON struct {
+	column string
+	sql    string
+	args   []interface{}
+	err    error
+}
+
+// JSONColumn is the whole document stored in column.
+func JSONColumn(column string) JSON {
+	return JSON{column: column, sql: quoteKey(column)}
+}
+
+// JSONExtract renders JSON_EXTRACT(column, path).
+func JSONExtract(column, path string) JSON {
+	return JSON{
+		column: column,
+		sql:    "JSON_EXTRACT(" + quoteKey(column) + ", ?)",
+		args:   []interface{}{path},
+		err:    validateJSONPath(path),
+	}
+}
+
+// JSONUnquote renders JSON_UNQUOTE(JSON_EXTRACT(column, path)), the scalar
+// found at path as text.
+func JSONUnquote(column, path string) JSON {
+	return JSONExtract(column, path).Unquote()
+}
+
+// JSONPath renders column->>'path'. MySQL only accepts a literal path in
+// this form, so the path is inlined once validated.
+func JSONPath(column, path string) JSON {
+	if err := validateJSONPath(path); err != nil {
+		return JSON{column: column, sql: "NULL", err: err}
+	}
+	return JSON{column: column, sql: quoteKey(column) + "->>'" + path + "'"}
+}
+
+func (j JSON) Unquote() JSON {
+	j.sql = "JSON_UNQUOTE(" + j.sql + ")"
+	return j
+}
+
+func (j JSON) String() string {
+	return j.sql
+}
+
+// Compare turns the expression into a predicate, e.g.
+// JSONUnquote("attrs", "$.color").Compare(Eq, "red").
+func (j JSON) Compare(operator Operator, value interface{}) Expr {
+	return jsonPredicate{target: j, compare: operator, value: value}
+}
+
+// Contains renders JSON_CONTAINS(target, value), value being marshalled to
+// JSON unless it is already a string or []byte.
+func (j JSON) Contains(value interface{}) Expr {
+	return jsonPredicate{target: j, function: "JSON_CONTAINS", value: value}
+}
+
+// Overlaps renders JSON_OVERLAPS(target, value).
+func (j JSON) Overlaps(value interface{}) Expr {
+	return jsonPredicate{target: j, function: "JSON_OVERLAPS", value: value}
+}
+
+// MemberOf renders value MEMBER OF(target).
+func (j JSON) MemberOf(value interface{}) Expr {
+	return jsonPredicate{target: j, member: true, value: value}
+}
+
+type jsonPredicate struct {
+	target   JSON
+	compare  Operator
+	function string
+	member   bool
+	value    interface{}
+}
+
+func (p jsonPredicate) expr(b *builder) (string, []interface{}) {
+	b.checkIdentifiers(p.target.column)
+	if p.target.err != nil {
+		b.fail(p.target.err)
+		return "FALSE", nil
+	}
+	values := append([]interface{}{}, p.target.args...)
+	var s strings.Builder
+	switch {
+	case p.member:
+		stmt, args, err := jsonArgument(p.value)
+		if err != nil {
+			b.fail(err)
+			return "FALSE", nil
+		}
+		s.WriteString(stmt)
+		s.WriteString(" MEMBER OF(")
+		s.WriteString(p.target.sql)
+		s.WriteString(")")
+		values = append(append([]interface{}{}, args...), values...)
+	case p.function != "":
+		document, err := jsonDocument(p.value)
+		if err != nil {
+			b.fail(fmt.Errorf("%w %s: %v", ErrInvalidValue, p.function, err))
+			return "FALSE", nil
+		}
+		s.WriteString(p.function)
+		s.WriteString("(")
+		s.WriteString(p.target.sql)
+		s.WriteString(", ?)")
+		values = append(values, document)
+	default:
+		stmt, args, err := buildComparison(p.target.sql, p.compare, p.value)
+		if err != nil {
+			b.fail(err)
+		}
+		s.WriteString(stmt)
+		values = append(values, args...)
+	}
+	return s.String(), values
+}
+
+// SelectJSON selects j as alias.
+func (b *builder) SelectJSON(j JSON, alias string) Builder {
+	b.checkIdentifiers(j.column)
+	if j.err != nil {
+		b.fail(j.err)
+	}
+	b.Select(j.sql + " AS " + quoteKey(alias))
+	b.selectValues = append(b.selectValues, j.args...)
+	return b
+}
+
+// JSONSet is an Update value setting each path, value pair in the document
+// of column: Update(map[string]interface{}{"attrs": JSONSet("attrs", "$.a", 1)}).
+// Maps and slices are stored as JSON documents.
+func JSONSet(column string, pairs ...interface{}) RawExpr {
+	if len(pairs) == 0 || len(pairs)%2 != 0 {
+		return RawExpr{sql: quoteKey(column), err: fmt.Errorf("%w JSON_SET: takes path, value pairs", ErrInvalidValue)}
+	}
+	var s strings.Builder
+	var args []interface{}
+	s.WriteString("JSON_SET(")
+	s.WriteString(quoteKey(column))
+	for i := 0; i < len(pairs); i += 2 {
+		path, ok := pairs[i].(string)
+		if !ok {
+			return RawExpr{sql: quoteKey(column), err: fmt.Errorf("%w: %v", ErrInvalidJSONPath, pairs[i])}
+		}
+		if err := validateJSONPath(path); err != nil {
+			return RawExpr{sql: quoteKey(column), err: err}
+		}
+		stmt, values, err := jsonArgument(pairs[i+1])
+		if err != nil {
+			return RawExpr{sql: quoteKey(column), err: err}
+		}
+		s.WriteString(", ?, ")
+		s.WriteString(stmt)
+		args = append(args, path)
+		args = append(args, values...)
+	}
+	s.WriteString(")")
+	return RawExpr{sql: s.String(), args: args}
+}
+
+// JSONRemove is an Update value removing paths from the document of column.
+func JSONRemove(column string, paths ...string) RawExpr {
+	var s strings.Builder
+	var args []interface{}
+	s.WriteString("JSON_REMOVE(")
+	s.WriteString(quoteKey(column))
+	for _, path := range paths {
+		if err := validateJSONPath(path); err != nil {
+			return RawExpr{sql: quoteKey(column), err: err}
+		}
+		s.WriteString(", ?")
+		args = append(args, path)
+	}
+	s.WriteString(")")
+	return RawExpr{sql: s.String(), args: args}
+}
+
+// jsonArgument binds value as a JSON function argument, casting maps and
+// slices so they are stored as documents rather than strings.
+func jsonArgument(value interface{}) (string, []interface{}, error) {
+	if raw, ok := rawValue(value); ok {
+		return raw.sql, raw.args, raw.err
+	}
+	if value != nil {
+		switch reflect.TypeOf(value).Kind() {
+		case reflect.Map, reflect.Slice, reflect.Array:
+			if _, ok := value.([]byte); !ok {
+				document, err := jsonDocument(value)
+				if err != nil {
+					return "", nil, err
+				}
+				return "CAST(? AS JSON)", []interface{}{document}, nil
+			}
+		}
+	}
+	return "?", []interface{}{value}, nil
+}
@@ -0,0 +1,60 @@
+package builder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestJSONPredicates(t *testing.T) {
+	query, values := New().
+		Select("`id`").
+		SelectJSON(JSONUnquote("attrs", "$.color"), "color").
+		From("`product`").
+		Where(JSONUnquote("attrs", "$.color").Compare(Eq, "red")).
+		Where(JSONColumn("tags").Contains([]string{"sale"})).
+		Where(JSONExtract("attrs", "$.sizes").Overlaps([]int{40, 41})).
+		Where(JSONColumn("tags").MemberOf("new")).
+		Where(JSONPath("attrs", "$.brand").Compare(Ne, nil)).
+		Build()
+	expected := "SELECT `id`,JSON_UNQUOTE(JSON_EXTRACT(`attrs`, ?)) AS `color` FROM `product` " +
+		"WHERE JSON_UNQUOTE(JSON_EXTRACT(`attrs`, ?)) = ? AND JSON_CONTAINS(`tags`, ?) AND " +
+		"JSON_OVERLAPS(JSON_EXTRACT(`attrs`, ?), ?) AND ? MEMBER OF(`tags`) AND `attrs`->>'$.brand' IS NOT NULL"
+	if query != expected {
+		t.Fatalf("query:\n got %q\nwant %q", query, expected)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"$.color", "$.color", "red", `["sale"]`, "$.sizes", "[40,41]", "new"}) {
+		t.Fatalf("got %#v", values)
+	}
+
+	b := New().Select("*").From("`product`").Where(JSONPath("attrs", "$.a' OR '1").Compare(Eq, 1))
+	query, _ = b.Build()
+	if query != "SELECT * FROM `product` WHERE FALSE" || !errors.Is(b.Err(), ErrInvalidJSONPath) {
+		t.Fatalf("got %q %v", query, b.Err())
+	}
+	if got := quoteKey("attrs->>'$.a' OR '1'"); got != "`attrs->>'$`.`a' OR '1'`" {
+		t.Fatalf("got %q", got)
+	}
+	if got := quoteKey("attrs->'$.a[0]'"); got != "`attrs`->'$.a[0]'" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestJSONUpdate(t *testing.T) {
+	query, values := New().Table("`product`").Update(map[string]interface{}{
+		"attrs": JSONSet("attrs", "$.color", "blue", "$.sizes", []int{1, 2}),
+		"tags":  JSONRemove("tags", "$[0]"),
+	}).Equal("id", 3).Build()
+	expected := "UPDATE `product` SET `attrs`=JSON_SET(`attrs`, ?, ?, ?, CAST(? AS JSON)),`tags`=JSON_REMOVE(`tags`, ?) WHERE `id` = ?"
+	if query != expected {
+		t.Fatalf("query:\n got %q\nwant %q", query, expected)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"$.color", "blue", "$.sizes", "[1,2]", "$[0]", 3}) {
+		t.Fatalf("got %#v", values)
+	}
+
+	b := New().Table("`product`").Update(map[string]interface{}{"attrs": JSONSet("attrs", "color", 1)})
+	if !errors.Is(b.Err(), ErrInvalidJSONPath) {
+		t.Fatalf("got %v", b.Err())
+	}
+}
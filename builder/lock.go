@@ -0,0 +1,51 @@
+package builder
+
+import "strings"
+
+func (b *builder) ForUpdate() Builder {
+	b.lock = "FOR UPDATE"
+	return b
+}
+
+func (b *builder) ForShare() Builder {
+	b.lock = "FOR SHARE"
+	return b
+}
+
+// Of restricts the locking clause to tables, given by name or alias.
+func (b *builder) Of(tables ...string) Builder {
+	b.lockTables = append(b.lockTables, tables...)
+	return b
+}
+
+func (b *builder) NoWait() Builder {
+	b.lockWait = "NOWAIT"
+	return b
+}
+
+func (b *builder) SkipLocked() Builder {
+	b.lockWait = "SKIP LOCKED"
+	return b
+}
+
+// Locked reports whether the select carries a locking clause, which only
+// holds inside a transaction.
+func (b *builder) Locked() bool {
+	return b.lock != ""
+}
+
+func (b *builder) buildLock(query *strings.Builder) {
+	if b.lock == "" {
+		return
+	}
+	query.WriteString(" ")
+	query.WriteString(b.lock)
+	if len(b.lockTables) > 0 {
+		query.WriteString(" OF ")
+		query.WriteString(strings.Join(b.lockTables, ", "))
+	}
+	if b.lockWait != "" {
+		query.WriteString(" ")
+		query.WriteString(b.lockWait)
+	}
+}
@@ -20,6 +20,7 @@ type QueryParams struct {
 	BetweenTime          map[string][]time.Time
 	UseDistinct          bool
 	UsePreparedStatement bool
+	Timezone             *time.Location
 }
 
 func (q *QueryParams) Clone() QueryParams {
@@ -48,28 +49,39 @@ func (q *QueryParams) Clone() QueryParams {
 	columnfilters := make([]string, 0)
 	columnfilters = append(columnfilters, q.ColumnFilter...)
 
+	priorities := make([]string, 0)
+	priorities = append(priorities, q.Priorities...)
+
 	betweentime := make(map[string][]time.Time)
 	for key, value := range q.BetweenTime {
 		betweentime[key] = make([]time.Time, 0)
 		betweentime[key] = append(betweentime[key], value...)
 	}
-	return QueryParams{
-		Object:       q.Object,
-		In:           in,
-		Notin:        notin,
-		Conditions:   conditions,
-		Next:         q.Next,
-		Page:         q.Page,
-		Size:         q.Size,
-		Orderby:      orderby,
-		Groupby:      groupby,
-		ColumnFilter: columnfilters,
-		BetweenTime:  betweentime,
-		Merge: &Merge{
+	var merge *Merge
+	if q.Merge != nil {
+		merge = &Merge{
 			Track:          q.Merge.Track,
 			Operation:      q.Merge.Operation,
 			ShouldContinue: q.Merge.ShouldContinue,
-		},
+		}
+	}
+	return QueryParams{
+		Object:               q.Object,
+		In:                   in,
+		Notin:                notin,
+		Conditions:           conditions,
+		Next:                 q.Next,
+		Page:                 q.Page,
+		Size:                 q.Size,
+		Orderby:              orderby,
+		Groupby:              groupby,
+		ColumnFilter:         columnfilters,
+		Priorities:           priorities,
+		BetweenTime:          betweentime,
+		UseDistinct:          q.UseDistinct,
+		UsePreparedStatement: q.UsePreparedStatement,
+		Timezone:             q.Timezone,
+		Merge:                merge,
 	}
 }
 
@@ -0,0 +1,71 @@
+package builder
+
+// OrderedMap is a map that remembers the order its keys were set in, for
+// callers who want to control the column order of Insert, Update, Upsert
+// and In instead of the default alphabetical one:
+//
+//	data := builder.NewOrderedMap().Set("name", name).Set("age", age)
+//	New().Table("`user`").Insert(data.Map(), data.Keys()...)
+type OrderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func NewOrderedMap() *OrderedMap {
+	return &OrderedMap{values: make(map[string]interface{})}
+}
+
+func (m *OrderedMap) Set(key string, value interface{}) *OrderedMap {
+	if m.values == nil {
+		m.values = make(map[string]interface{})
+	}
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+	return m
+}
+
+func (m *OrderedMap) Get(key string) (interface{}, bool) {
+	value, ok := m.values[key]
+	return value, ok
+}
+
+func (m *OrderedMap) Delete(key string) *OrderedMap {
+	if _, ok := m.values[key]; !ok {
+		return m
+	}
+	delete(m.values, key)
+	for i, item := range m.keys {
+		if item == key {
+			m.keys = append(m.keys[:i:i], m.keys[i+1:]...)
+			break
+		}
+	}
+	return m
+}
+
+func (m *OrderedMap) Len() int {
+	return len(m.keys)
+}
+
+// Keys returns the keys in the order they were first set.
+func (m *OrderedMap) Keys() []string {
+	keys := make([]string, len(m.keys))
+	copy(keys, m.keys)
+	return keys
+}
+
+// Map returns a copy of the entries as a plain map. Nil values are returned
+// as Null, since the explicit columns given by Keys skip nil values and
+// would otherwise drop them.
+func (m *OrderedMap) Map() map[string]interface{} {
+	values := make(map[string]interface{}, len(m.values))
+	for key, value := range m.values {
+		if value == nil {
+			value = Null
+		}
+		values[key] = value
+	}
+	return values
+}
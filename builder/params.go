@@ -0,0 +1,82 @@
+package builder
+
+import (
+	"sort"
+	"strings"
+)
+
+// FromParams compiles p into a select over table. Every column name is passed
+// through ResolveColumnName; an empty ColumnFilter selects all columns.
+// Priorities are ordered ahead of Orderby, highest value first. Next is
+// applied with After, which also takes over the ordering of its column.
+// Times are converted to Timezone when it is set.
+func FromParams(table string, p QueryParams) Builder {
+	b := New().From(table).Timezone(p.Timezone)
+	if p.UseDistinct {
+		b.Distinct()
+	}
+	if len(p.ColumnFilter) > 0 {
+		b.Select(strings.Join(ResolveColumnNameCollections(p.ColumnFilter), ","))
+	} else {
+		b.Select("*")
+	}
+	and := func() {
+		if _, where, _ := b.Status(); where > 0 {
+			b.And()
+		}
+	}
+	if len(p.In) > 0 {
+		and()
+		b.In(ResolveColumnNameMap(p.In))
+	}
+	if len(p.Notin) > 0 {
+		and()
+		b.NotIn(ResolveColumnNameMap(p.Notin))
+	}
+	if len(p.Conditions) > 0 {
+		conditions := make([]Condition, 0, len(p.Conditions))
+		for _, item := range p.Conditions {
+			item.Key = ResolveColumnName(item.Key)
+			conditions = append(conditions, item)
+		}
+		and()
+		b.Compare(conditions)
+	}
+	if len(p.BetweenTime) > 0 {
+		between := ResolveColumnNameMapInTime(p.BetweenTime)
+		keys := make([]string, 0, len(between))
+		for key := range between {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			if len(between[key]) < 2 {
+				continue
+			}
+			and()
+			b.BetweenTime(key, between[key][0], between[key][1])
+		}
+	}
+	var next string
+	if p.Next != nil {
+		key := *p.Next
+		key.Column = ResolveColumnName(key.Column)
+		next = key.Column
+		and()
+		b.After(key)
+	}
+	if len(p.Groupby) > 0 {
+		b.Groups(ResolveColumnNameCollections(p.Groupby))
+	}
+	for _, column := range p.Priorities {
+		b.Order(OrderBy{Column: ResolveColumnName(column), Direction: "DESC"})
+	}
+	for _, order := range p.Orderby {
+		order.Column = ResolveColumnName(order.Column)
+		if order.Column == next {
+			continue
+		}
+		b.Order(order)
+	}
+	return b.Page(p.Page).Size(p.Size)
+}
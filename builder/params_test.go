@@ -0,0 +1,125 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestFromParams(t *testing.T) {
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+	cases := []struct {
+		name   string
+		params QueryParams
+		query  string
+		values []interface{}
+	}{
+		{
+			name:   "empty",
+			params: QueryParams{},
+			query:  "SELECT * FROM `post`",
+		},
+		{
+			name:   "column filter",
+			params: QueryParams{ColumnFilter: []string{"id", "userName"}},
+			query:  "SELECT `id`,`user_name` FROM `post`",
+		},
+		{
+			name:   "distinct",
+			params: QueryParams{ColumnFilter: []string{"userId"}, UseDistinct: true},
+			query:  "SELECT DISTINCT `user_id` FROM `post`",
+		},
+		{
+			name:   "in",
+			params: QueryParams{In: map[string]interface{}{"statusId": []int{1, 2}}},
+			query:  "SELECT * FROM `post` WHERE `status_id` IN (?,?) ",
+			values: []interface{}{1, 2},
+		},
+		{
+			name:   "not in",
+			params: QueryParams{Notin: map[string]interface{}{"statusId": []int{3}}},
+			query:  "SELECT * FROM `post` WHERE `status_id` NOT IN (?) ",
+			values: []interface{}{3},
+		},
+		{
+			name: "in and not in",
+			params: QueryParams{
+				In:    map[string]interface{}{"statusId": []int{1}},
+				Notin: map[string]interface{}{"typeId": []int{3}},
+			},
+			query:  "SELECT * FROM `post` WHERE `status_id` IN (?)  AND `type_id` NOT IN (?) ",
+			values: []interface{}{1, 3},
+		},
+		{
+			name: "conditions",
+			params: QueryParams{Conditions: []Condition{
+				{Key: "userId", Operator: "=", Value: 7},
+				{Key: "title", Operator: "like", Value: "%go%"},
+			}},
+			query:  "SELECT * FROM `post` WHERE `user_id` = ? AND `title` LIKE ?",
+			values: []interface{}{7, "%go%"},
+		},
+		{
+			name:   "between time",
+			params: QueryParams{BetweenTime: map[string][]time.Time{"createdAt": {from, to}}},
+			query:  "SELECT * FROM `post` WHERE `created_at` BETWEEN ? AND ?",
+			values: []interface{}{"2024-01-01 00:00:00", "2024-01-02 00:00:00"},
+		},
+		{
+			name:   "between time without upper bound",
+			params: QueryParams{BetweenTime: map[string][]time.Time{"createdAt": {from}}},
+			query:  "SELECT * FROM `post`",
+		},
+		{
+			name:   "next",
+			params: QueryParams{Next: &Next{Column: "id", Direction: "desc", Value: 10}},
+			query:  "SELECT * FROM `post` WHERE `id` < ? ORDER BY `id` DESC",
+			values: []interface{}{10},
+		},
+		{
+			name:   "page and size",
+			params: QueryParams{Page: 3, Size: 10},
+			query:  "SELECT * FROM `post` LIMIT 10  OFFSET 20 ",
+		},
+		{
+			name:   "orderby",
+			params: QueryParams{Orderby: []OrderBy{{Column: "createdAt", Direction: "DESC"}}},
+			query:  "SELECT * FROM `post` ORDER BY `created_at` DESC",
+		},
+		{
+			name:   "groupby",
+			params: QueryParams{ColumnFilter: []string{"userId"}, Groupby: []string{"userId"}},
+			query:  "SELECT `user_id` FROM `post` GROUP BY `user_id`",
+		},
+		{
+			name: "priorities",
+			params: QueryParams{
+				Priorities: []string{"isPinned"},
+				Orderby:    []OrderBy{{Column: "createdAt", Direction: "DESC"}},
+			},
+			query: "SELECT * FROM `post` ORDER BY `is_pinned` DESC,`created_at` DESC",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			query, values := FromParams("`post`", c.params).Build()
+			if query != c.query {
+				t.Fatalf("query:\n got %q\nwant %q", query, c.query)
+			}
+			if len(values) != 0 || len(c.values) != 0 {
+				if !reflect.DeepEqual(values, c.values) {
+					t.Fatalf("values:\n got %#v\nwant %#v", values, c.values)
+				}
+			}
+			cloned, clonedValues := FromParams("`post`", c.params.Clone()).Build()
+			if cloned != query || !reflect.DeepEqual(clonedValues, values) {
+				t.Fatalf("clone:\n got %q %#v\nwant %q %#v", cloned, clonedValues, query, values)
+			}
+		})
+	}
+	p := QueryParams{UseDistinct: true, UsePreparedStatement: true, Priorities: []string{"p"}}
+	if c := p.Clone(); !c.UseDistinct || !c.UsePreparedStatement || !reflect.DeepEqual(c.Priorities, p.Priorities) {
+		t.Fatalf("got %+v", c)
+	}
+}
@@ -1,6 +1,7 @@
 package builder
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -8,38 +9,92 @@ import (
 
 const DateTimeFormat = "2006-01-02 15:04:05"
 
+var (
+	ErrMultiTableLimit  = errors.New("ORDER BY and LIMIT need a single-table UPDATE or DELETE")
+	ErrModifierOffset   = errors.New("OFFSET is not supported on UPDATE or DELETE")
+	ErrInvalidDirection = errors.New("order direction must be ASC or DESC")
+)
+
 type Builder interface {
 	Insert(data map[string]interface{}, columns ...string) Builder
 	Update(data map[string]interface{}, columns ...string) Builder
 	Upsert(data map[string]interface{}, columns ...string) Builder
-	Delete() Builder
+	FromSelect(other Builder, columns ...string) Builder
+	Ignore() Builder
+	Replace() Builder
+	Dialect(dialect Dialect) Builder
+	OnConflict(assignments ...Assignment) Builder
+	DoNothing() Builder
+	RowAlias(alias string) Builder
+	ConflictTarget(columns ...string) Builder
+	InsertStruct(v interface{}) Builder
+	UpdateStruct(v interface{}, pk ...string) Builder
+	UpsertStruct(v interface{}) Builder
+	InsertMany(rows []map[string]interface{}, columns ...string) []Batch
+	UpsertMany(rows []map[string]interface{}, columns ...string) []Batch
+	MaxAllowedPacket(bytes int) Builder
+	Delete(tables ...string) Builder
 	Explain() Builder
+	With(name string, other Builder) Builder
+	WithRecursive(name string, anchor, recursive Builder) Builder
 	Select(field string) Builder
+	Distinct() Builder
+	SelectSubquery(other Builder, alias string) Builder
+	SelectAggregate(aggregates ...Aggregate) Builder
+	SelectWindow(functions ...WindowFunction) Builder
+	SelectMatch(m Fulltext, alias string) Builder
+	SelectJSON(j JSON, alias string) Builder
 	Table(table string, alias ...string) Builder
 	From(table string, alias ...string) Builder
 	Join(table string, on string, alias ...string) Builder
 	LeftJoin(table string, on string, alias ...string) Builder
 	RightJoin(table string, on string, alias ...string) Builder
+	FromSubquery(other Builder, alias string) Builder
+	JoinSubquery(other Builder, on string, alias string) Builder
+	LeftJoinSubquery(other Builder, on string, alias string) Builder
 	Statement(stmt string, values []interface{}) Builder
-	In(in map[string]interface{}) Builder
+	In(in map[string]interface{}, columns ...string) Builder
+	NotIn(notin map[string]interface{}, columns ...string) Builder
 	Exists(other Builder, condition Condition) Builder
+	NotExists(other Builder, condition Condition) Builder
+	InSubquery(column string, other Builder) Builder
 	Alias(name string) string
 	Compare(conditions []Condition) Builder
+	Allow(table string, columns ...string) Builder
+	Where(expr Expr) Builder
 	NotEqual(column string, value interface{}) Builder
 	Equal(column string, value interface{}) Builder
 	BetweenTime(column string, from, to time.Time) Builder
+	Between(column string, from, to interface{}) Builder
+	Range(column string, from, to interface{}) Builder
+	Timezone(loc *time.Location) Builder
+	After(next Next) Builder
+	Seek(keys ...Next) Builder
 	Page(index int) Builder
 	Size(n int) Builder
 	Order(order OrderBy) Builder
 	Orders(orders []OrderBy) Builder
 	Group(column string) Builder
 	Groups(column []string) Builder
+	Rollup() Builder
+	Having(expr Expr) Builder
+	Window(name string, window Window) Builder
+	ForUpdate() Builder
+	ForShare() Builder
+	Of(tables ...string) Builder
+	NoWait() Builder
+	SkipLocked() Builder
+	Locked() bool
 	And() Builder
 	Or() Builder
 	Not(Builder) Builder
 	Status() (int, int, int)
 	Reset(section string) Builder
 	Build() (string, []interface{})
+	BuildCount() (string, []interface{})
+	Clone() Builder
+	String() string
+	Err() error
 }
 
 func New() Builder {
@@ -47,46 +102,90 @@ func New() Builder {
 }
 
 type builder struct {
-	operator        []string
-	source          []map[string]string
-	selectStatement strings.Builder
-	whereStatement  strings.Builder
-	orderStatement  strings.Builder
-	groupStatement  strings.Builder
-	values          []interface{}
-	page            int
-	size            int
-	explain         bool
-	upsert          map[string]interface{}
-	update          map[string]interface{}
-	insert          map[string]interface{}
-	delete          bool
-	columns         []string
+	operator         []string
+	source           []map[string]string
+	sourceValues     map[int][]interface{}
+	withStatement    strings.Builder
+	withValues       []interface{}
+	recursive        bool
+	compound         []Builder
+	setOperator      string
+	selectStatement  strings.Builder
+	selectValues     []interface{}
+	whereStatement   strings.Builder
+	orderStatement   strings.Builder
+	orderValues      []interface{}
+	groupStatement   strings.Builder
+	rollup           bool
+	havingStatement  strings.Builder
+	havingValues     []interface{}
+	windowStatement  strings.Builder
+	windowValues     []interface{}
+	lock             string
+	lockTables       []string
+	lockWait         string
+	values           []interface{}
+	page             int
+	size             int
+	explain          bool
+	distinct         bool
+	upsert           map[string]interface{}
+	update           map[string]interface{}
+	insert           map[string]interface{}
+	insertSelect     Builder
+	insertModifier   string
+	conflict         conflict
+	dialect          Dialect
+	delete           bool
+	deleteTargets    []string
+	columns          []string
+	maxAllowedPacket int
+	allow            map[string]map[string]bool
+	identifiers      []string
+	location         *time.Location
+	err              error
 }
 
 func (b *builder) Insert(data map[string]interface{}, columns ...string) Builder {
 	b.insert = data
 	b.columns = columns
+	b.checkData(data, columns)
 	return b
 }
 func (b *builder) Update(data map[string]interface{}, columns ...string) Builder {
 	b.update = data
 	b.columns = columns
+	b.checkData(data, columns)
 	return b
 }
+
+// Upsert inserts data and, on a duplicate key, updates every inserted column
+// with its inserted value unless OnConflict says otherwise. A Raw value is
+// inlined on both sides, into VALUES and into the update, so an expression
+// on the existing row such as `key` + 1 belongs in
+// OnConflict(Increment("key", 1)) instead.
 func (b *builder) Upsert(data map[string]interface{}, columns ...string) Builder {
 	b.upsert = data
 	b.columns = columns
+	b.checkData(data, columns)
 	return b
 }
-func (b *builder) Delete() Builder {
+
+// Delete removes rows from the first table, or from tables when given,
+// which switches to the multi-table form so joined tables can be targeted.
+func (b *builder) Delete(tables ...string) Builder {
 	b.delete = true
+	b.deleteTargets = tables
 	return b
 }
 func (b *builder) Explain() Builder {
 	b.explain = true
 	return b
 }
+func (b *builder) Distinct() Builder {
+	b.distinct = true
+	return b
+}
 func (b *builder) Select(field string) Builder {
 	if b.selectStatement.Len() > 0 {
 		b.selectStatement.WriteString(",")
@@ -161,120 +260,92 @@ func (b *builder) Alias(name string) string {
 }
 
 func (b *builder) Statement(stmt string, values []interface{}) Builder {
-	if b.whereStatement.Len() > 0 {
-		b.whereStatement.WriteString(b.operator[0])
-		b.operator = b.operator[1:]
-	}
+	b.conjunction()
 	b.whereStatement.WriteString(stmt)
 	b.values = append(b.values, values...)
 	return b
 }
 
-func (b *builder) In(in map[string]interface{}) Builder {
-	if b.whereStatement.Len() > 0 {
-		b.whereStatement.WriteString(b.operator[0])
-		b.operator = b.operator[1:]
-	}
-	first := true
-	for key, value := range in {
-		if query, values := buildInStatement(key, value); len(query) > 0 {
-			if !first {
-				b.whereStatement.WriteString(" AND ")
+func (b *builder) In(in map[string]interface{}, columns ...string) Builder {
+	return b.membership(in, columns, "IN")
+}
+
+func (b *builder) NotIn(notin map[string]interface{}, columns ...string) Builder {
+	return b.membership(notin, columns, "NOT IN")
+}
+
+func (b *builder) membership(in map[string]interface{}, columns []string, operator string) Builder {
+	var stmt strings.Builder
+	var values []interface{}
+	if len(columns) == 0 {
+		columns = sortedKeys(in)
+	}
+	for _, key := range columns {
+		value, ok := in[key]
+		if !ok {
+			continue
+		}
+		if query, tmp := buildMembershipStatement(b.identifier(key), operator, value); len(query) > 0 {
+			if stmt.Len() > 0 {
+				stmt.WriteString(" AND ")
 			}
-			b.whereStatement.WriteString(query)
-			b.values = append(b.values, values...)
-			first = false
+			stmt.WriteString(query)
+			values = append(values, tmp...)
 		}
 	}
+	if stmt.Len() > 0 {
+		b.conjunction()
+		b.whereStatement.WriteString(stmt.String())
+		b.values = append(b.values, values...)
+	}
 	return b
 }
 
 func (b *builder) Exists(other Builder, condition Condition) Builder {
-	if b.whereStatement.Len() > 0 {
-		b.whereStatement.WriteString(b.operator[0])
-		b.operator = b.operator[1:]
-	}
-	_other := other.(*builder)
-	var tmp strings.Builder
-	conditionStatement, _ := buildConditionStatement(condition)
-	tmp.WriteString(conditionStatement)
-	if _other.whereStatement.Len() > 0 {
-		tmp.WriteString(" AND ")
-	}
-	tmp.WriteString(_other.whereStatement.String())
-	_other.whereStatement = tmp
-	b.whereStatement.WriteString(" EXISTS (")
-	innerStatement, values := _other.Build()
-	b.whereStatement.WriteString(innerStatement)
-	b.whereStatement.WriteString(")")
-	b.values = append(b.values, values...)
-	return b
+	return b.exists("EXISTS", other, condition)
 }
 
 func (b *builder) Compare(conditions []Condition) Builder {
 	var compStatement strings.Builder
 	for _, item := range conditions {
-		stmt, value := buildConditionStatement(item)
+		stmt, values := item.expr(b)
 		if len(stmt) > 0 {
 			if compStatement.Len() > 0 {
 				compStatement.WriteString(" AND ")
 			}
 			compStatement.WriteString(stmt)
 		}
-		if value != nil {
-			b.values = append(b.values, value)
-		}
+		b.values = append(b.values, values...)
 	}
 	if compStatement.Len() > 0 {
-		if b.whereStatement.Len() > 0 {
-			b.whereStatement.WriteString(b.operator[0])
-			b.operator = b.operator[1:]
-		}
+		b.conjunction()
 		b.whereStatement.WriteString(compStatement.String())
 	}
 	return b
 }
 
 func (b *builder) NotEqual(column string, value interface{}) Builder {
-	if !strings.Contains(column, "`") {
-		column = "`" + column + "`"
-	}
-	if b.whereStatement.Len() > 0 {
-		b.whereStatement.WriteString(b.operator[0])
-		b.operator = b.operator[1:]
-	}
+	column = b.identifier(column)
+	b.conjunction()
 	b.whereStatement.WriteString(column)
 	b.whereStatement.WriteString(" <> ")
-	b.whereStatement.WriteString("?")
-	b.values = append(b.values, value)
+	b.values = append(b.values, writeValue(&b.whereStatement, value)...)
 	return b
 }
 func (b *builder) Equal(column string, value interface{}) Builder {
-	if !strings.Contains(column, "`") {
-		column = "`" + column + "`"
-	}
-	if b.whereStatement.Len() > 0 {
-		b.whereStatement.WriteString(b.operator[0])
-		b.operator = b.operator[1:]
-	}
+	column = b.identifier(column)
+	b.conjunction()
 	b.whereStatement.WriteString(column)
 	b.whereStatement.WriteString(" = ")
-	b.whereStatement.WriteString("?")
-	b.values = append(b.values, value)
+	b.values = append(b.values, writeValue(&b.whereStatement, value)...)
 	return b
 }
 func (b *builder) BetweenTime(column string, from, to time.Time) Builder {
-	if !strings.Contains(column, "`") {
-		column = "`" + column + "`"
-	}
-	if b.whereStatement.Len() > 0 {
-		b.whereStatement.WriteString(b.operator[0])
-		b.operator = b.operator[1:]
-	}
+	column = b.identifier(column)
+	b.conjunction()
 	b.whereStatement.WriteString(column)
-	b.whereStatement.WriteString(" BETWEEN ? AND ? ")
-	b.values = append(b.values, from.Format(DateTimeFormat))
-	b.values = append(b.values, to.Format(DateTimeFormat))
+	b.whereStatement.WriteString(" BETWEEN ? AND ?")
+	b.values = append(b.values, formattedTime(from), formattedTime(to))
 	return b
 }
 func (b *builder) Page(index int) Builder {
@@ -291,30 +362,14 @@ func (b *builder) Size(n int) Builder {
 	return b
 }
 func (b *builder) Order(order OrderBy) Builder {
-	if !strings.Contains(order.Column, "`") {
-		order.Column = "`" + order.Column + "`"
-	}
+	order.Column = b.identifier(order.Column)
 	if b.orderStatement.Len() > 0 {
 		b.orderStatement.WriteString(",")
 	}
-	if len(order.Fields) > 0 {
-		b.orderStatement.WriteString("Field(")
-		b.orderStatement.WriteString(order.Column)
-		b.orderStatement.WriteString(",")
-		for i, field := range order.Fields {
-			if i > 0 {
-				b.orderStatement.WriteString(",")
-			}
-			b.orderStatement.WriteString("'")
-			b.orderStatement.WriteString(field)
-			b.orderStatement.WriteString("'")
-		}
-		b.orderStatement.WriteString(")")
-	} else {
-		b.orderStatement.WriteString(order.Column)
-		b.orderStatement.WriteString(" ")
-		b.orderStatement.WriteString(order.Direction)
-	}
+	stmt, values, err := buildOrder(order)
+	b.fail(err)
+	b.orderStatement.WriteString(stmt)
+	b.orderValues = append(b.orderValues, values...)
 	return b
 }
 func (b *builder) Orders(orders []OrderBy) Builder {
@@ -324,28 +379,56 @@ func (b *builder) Orders(orders []OrderBy) Builder {
 	return b
 }
 func (b *builder) Group(column string) Builder {
-	if !strings.Contains(column, "`") {
-		column = "`" + column + "`"
-	}
+	column = b.identifier(column)
 	if b.groupStatement.Len() > 0 {
 		b.groupStatement.WriteString(",")
 	}
 	b.groupStatement.WriteString(column)
-	b.groupStatement.WriteString(" ")
 	return b
 }
 func (b *builder) Groups(columns []string) Builder {
 	for _, column := range columns {
-		if !strings.Contains(column, "`") {
-			column = "`" + column + "`"
-		}
-		if b.groupStatement.Len() > 0 {
-			b.groupStatement.WriteString(",")
+		b.Group(column)
+	}
+	return b
+}
+func (b *builder) Rollup() Builder {
+	b.rollup = true
+	return b
+}
+func (b *builder) Having(expr Expr) Builder {
+	stmt, values := renderExpr(b, expr)
+	if len(stmt) > 0 {
+		if b.havingStatement.Len() > 0 {
+			b.havingStatement.WriteString(" AND ")
 		}
-		b.groupStatement.WriteString(column)
-		b.groupStatement.WriteString(" ")
+		b.havingStatement.WriteString(stmt)
+		b.havingValues = append(b.havingValues, values...)
+	}
+	return b
+}
+
+// conjunction joins the next where clause with the operator queued by And or
+// Or, falling back to AND when none was queued.
+func (b *builder) conjunction() {
+	if b.whereStatement.Len() == 0 {
+		return
 	}
+	if len(b.operator) == 0 {
+		b.whereStatement.WriteString(" AND ")
+		return
+	}
+	b.whereStatement.WriteString(b.operator[0])
+	b.operator = b.operator[1:]
+}
 
+func (b *builder) Where(expr Expr) Builder {
+	stmt, values := renderExpr(b, expr)
+	if len(stmt) > 0 {
+		b.conjunction()
+		b.whereStatement.WriteString(stmt)
+		b.values = append(b.values, values...)
+	}
 	return b
 }
 func (b *builder) And() Builder {
@@ -357,10 +440,7 @@ func (b *builder) Or() Builder {
 	return b
 }
 func (b *builder) Not(inner Builder) Builder {
-	if b.whereStatement.Len() > 0 {
-		b.whereStatement.WriteString(b.operator[0])
-		b.operator = b.operator[1:]
-	}
+	b.conjunction()
 	_inner := inner.(*builder)
 	b.whereStatement.WriteString("not(")
 	b.whereStatement.WriteString(_inner.whereStatement.String())
@@ -375,73 +455,169 @@ func (b *builder) Reset(section string) Builder {
 	switch section {
 	case "select", "Select", "SELECT":
 		b.selectStatement.Reset()
+		b.selectValues = nil
 	case "from":
 		b.source = make([]map[string]string, 0)
+		b.sourceValues = nil
 	case "where", "Where", "WHERE":
 		b.whereStatement.Reset()
+		b.values = nil
+		b.operator = nil
 	case "orderby", "Orderby", "ORDERBY":
 		b.orderStatement.Reset()
+		b.orderValues = nil
 	case "table", "Table", "TABLE":
 		b.source = nil
+		b.sourceValues = nil
 	}
 	return b
 }
+
+// Err reports the first error met while composing the query, or else the
+// first problem of the statement as it stands. A builder with an error
+// still builds, but its output should not be executed.
+func (b *builder) Err() error {
+	if b.err != nil {
+		return b.err
+	}
+	return b.validate()
+}
+
+// validate checks what is only known once the statement is complete. It
+// records nothing, so Build and Err never change the builder.
+func (b *builder) validate() error {
+	if err := b.identifiersErr(); err != nil {
+		return err
+	}
+	for _, part := range b.compound {
+		if err := part.Err(); err != nil {
+			return err
+		}
+	}
+	if len(b.compound) > 0 {
+		if err := b.compoundClauseErr(); err != nil {
+			return err
+		}
+	}
+	switch {
+	case b.insertSelect != nil, len(b.insert) > 0:
+		if b.insertSelect != nil {
+			if err := b.insertSelect.Err(); err != nil {
+				return err
+			}
+		}
+		if b.insertModifier == "REPLACE" && b.dialect == PostgreSQL {
+			return ErrUnsupportedDialect
+		}
+	case len(b.upsert) > 0:
+		_, _, err := buildUpsert(b.source[0]["table"], b.upsert, b.columns, b.conflictStrategy())
+		return err
+	case len(b.update) > 0 || b.delete:
+		return b.modifierLimitErr()
+	}
+	return nil
+}
+func (b *builder) fail(err error) {
+	if b.err == nil {
+		b.err = err
+	}
+}
 func (b *builder) Build() (string, []interface{}) {
+	query, values := b.render()
+	return rebind(b.dialect, query), values
+}
+
+// render builds the statement as MySQL, before rebinding to the dialect.
+func (b *builder) render() (string, []interface{}) {
+	query, values := b.build()
+	return query, b.localize(values)
+}
+func (b *builder) build() (string, []interface{}) {
 	var values []interface{}
 	var query strings.Builder
-	if len(b.insert) > 0 {
+	if b.insertSelect != nil {
+		verb, suffix := b.insertClause()
+		query.WriteString(verb)
+		query.WriteString(b.source[0]["table"])
+		if len(b.columns) > 0 {
+			query.WriteString("(")
+			for i, column := range b.columns {
+				if i > 0 {
+					query.WriteString(",")
+				}
+				column = quoteKey(column)
+				query.WriteString(column)
+			}
+			query.WriteString(")")
+		}
+		stmt, tmp := nested(b.insertSelect)
+		query.WriteString(" ")
+		query.WriteString(stmt)
+		query.WriteString(suffix)
+		values = tmp
+	} else if len(b.insert) > 0 {
+		verb, suffix := b.insertClause()
 		var stmt string
-		stmt, values = buildInsert(b.source[0]["table"], b.insert, b.columns)
+		stmt, values = buildInsert(verb, b.source[0]["table"], b.insert, b.columns, suffix)
 		query.WriteString(stmt)
 	} else if len(b.upsert) > 0 {
 		var stmt string
-		stmt, values = buildUpsert(b.source[0]["table"], b.upsert, b.columns)
+		stmt, values, _ = buildUpsert(b.source[0]["table"], b.upsert, b.columns, b.conflictStrategy())
 		query.WriteString(stmt)
 	} else if len(b.update) > 0 {
-		var stmt string
-		stmt, values = buildUpdate(b.source[0]["table"], b.update, b.columns)
+		values = b.buildWith(&query)
+		stmt, tmp := buildUpdate(b.buildSources(), b.update, b.columns)
 		query.WriteString(stmt)
+		values = append(values, b.sourceArgs()...)
+		values = append(values, tmp...)
 		if b.whereStatement.Len() > 0 {
 			query.WriteString(" ")
 			query.WriteString("WHERE ")
 			query.WriteString(b.whereStatement.String())
 			values = append(values, b.values...)
 		}
+		values = append(values, b.buildModifierLimit(&query)...)
 	} else if b.delete {
+		values = b.buildWith(&query)
 		query.WriteString("DELETE ")
+		if len(b.source) > 1 || len(b.deleteTargets) > 0 {
+			targets := b.deleteTargets
+			if len(targets) == 0 {
+				targets = []string{b.Alias(b.source[0]["table"])}
+			}
+			query.WriteString(strings.Join(targets, ", "))
+			query.WriteString(" ")
+		}
 		query.WriteString("FROM ")
-		query.WriteString(b.source[0]["table"])
+		query.WriteString(b.buildSources())
+		values = append(values, b.sourceArgs()...)
 		if b.whereStatement.Len() > 0 {
+			query.WriteString(" ")
 			query.WriteString("WHERE ")
 			query.WriteString(b.whereStatement.String())
 		}
-		values = b.values
+		values = append(values, b.values...)
+		values = append(values, b.buildModifierLimit(&query)...)
+	} else if len(b.compound) > 0 {
+		if b.explain {
+			query.WriteString("EXPLAIN ")
+		}
+		values = b.buildWith(&query)
+		values = append(values, b.buildCompound(&query)...)
+		values = append(values, b.buildPagination(&query)...)
 	} else {
 		if b.explain {
 			query.WriteString("EXPLAIN ")
 		}
+		values = b.buildWith(&query)
 		query.WriteString("SELECT ")
+		if b.distinct {
+			query.WriteString("DISTINCT ")
+		}
 		query.WriteString(b.selectStatement.String())
 		query.WriteString(" ")
 		query.WriteString("FROM ")
-		query.WriteString(b.source[0]["table"])
-		if b.source[0]["alias"] != "" {
-			query.WriteString(" ")
-			query.WriteString(b.source[0]["alias"])
-		}
-		for _, src := range b.source[1:] {
-			query.WriteString(" ")
-			query.WriteString(src["operator"])
-			query.WriteString(" ")
-			query.WriteString(src["table"])
-			if src["alias"] != "" {
-				query.WriteString(" ")
-				query.WriteString(src["alias"])
-			}
-			query.WriteString(" ")
-			query.WriteString(" ON ")
-			query.WriteString(src["on"])
-		}
+		query.WriteString(b.buildSources())
 		if b.whereStatement.Len() > 0 {
 			query.WriteString(" ")
 			query.WriteString("WHERE ")
@@ -451,22 +627,103 @@ func (b *builder) Build() (string, []interface{}) {
 			query.WriteString(" ")
 			query.WriteString("GROUP BY ")
 			query.WriteString(b.groupStatement.String())
+			if b.rollup {
+				query.WriteString(" WITH ROLLUP")
+			}
 		}
-		if b.orderStatement.Len() > 0 {
+		if b.havingStatement.Len() > 0 {
 			query.WriteString(" ")
-			query.WriteString("ORDER BY ")
-			query.WriteString(b.orderStatement.String())
+			query.WriteString("HAVING ")
+			query.WriteString(b.havingStatement.String())
 		}
-		if b.size != 0 {
+		if b.windowStatement.Len() > 0 {
 			query.WriteString(" ")
-			query.WriteString(fmt.Sprintf("LIMIT %d ", b.size))
+			query.WriteString("WINDOW ")
+			query.WriteString(b.windowStatement.String())
 		}
-		if b.page != 0 {
+		orderValues := b.buildPagination(&query)
+		b.buildLock(&query)
+		values = append(values, b.selectValues...)
+		values = append(values, b.sourceArgs()...)
+		values = append(values, b.values...)
+		values = append(values, b.havingValues...)
+		values = append(values, b.windowValues...)
+		values = append(values, orderValues...)
+	}
+
+	return query.String(), values
+}
+
+func (b *builder) buildPagination(query *strings.Builder) []interface{} {
+	var values []interface{}
+	if b.orderStatement.Len() > 0 {
+		query.WriteString(" ")
+		query.WriteString("ORDER BY ")
+		query.WriteString(b.orderStatement.String())
+		values = b.orderValues
+	}
+	if b.size != 0 {
+		query.WriteString(" ")
+		query.WriteString(fmt.Sprintf("LIMIT %d ", b.size))
+	}
+	if b.page != 0 {
+		query.WriteString(" ")
+		query.WriteString(fmt.Sprintf("OFFSET %d ", b.page*b.size))
+	}
+	return values
+}
+
+// buildSources renders the first table and its joins, as used after FROM
+// or UPDATE.
+func (b *builder) buildSources() string {
+	var query strings.Builder
+	query.WriteString(b.source[0]["table"])
+	if b.source[0]["alias"] != "" {
+		query.WriteString(" ")
+		query.WriteString(b.source[0]["alias"])
+	}
+	for _, src := range b.source[1:] {
+		query.WriteString(" ")
+		query.WriteString(src["operator"])
+		query.WriteString(" ")
+		query.WriteString(src["table"])
+		if src["alias"] != "" {
 			query.WriteString(" ")
-			query.WriteString(fmt.Sprintf("OFFSET %d ", b.page*b.size))
+			query.WriteString(src["alias"])
 		}
-		values = b.values
+		query.WriteString(" ")
+		query.WriteString(" ON ")
+		query.WriteString(src["on"])
 	}
+	return query.String()
+}
 
-	return query.String(), values
+func (b *builder) sourceArgs() []interface{} {
+	var values []interface{}
+	for i := range b.source {
+		values = append(values, b.sourceValues[i]...)
+	}
+	return values
+}
+
+// buildModifierLimit writes ORDER BY and LIMIT of an UPDATE or DELETE,
+// which MySQL only accepts on a single table and without an offset.
+func (b *builder) buildModifierLimit(query *strings.Builder) []interface{} {
+	if b.modifierLimitErr() == nil {
+		return b.buildPagination(query)
+	}
+	return nil
+}
+
+func (b *builder) modifierLimitErr() error {
+	if b.orderStatement.Len() == 0 && b.size == 0 && b.page == 0 {
+		return nil
+	}
+	if len(b.source) > 1 || len(b.deleteTargets) > 0 {
+		return ErrMultiTableLimit
+	}
+	if b.page != 0 {
+		return ErrModifierOffset
+	}
+	return nil
 }
@@ -1,6 +1,9 @@
 package builder
 
 import (
+	"errors"
+	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -26,11 +29,12 @@ func TestQuery(t *testing.T) {
 	//create comparator
 	comparators := []Condition{{
 		Operator: "like",
-		Key:      "hello(`satu`)",
+		Key:      "satu",
 		Value:    "dua",
 	}}
 
-	query, values := New().
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	b := New().
 		Select("test").
 		From("`hello_world`").
 		Statement("", make([]interface{}, 0)).
@@ -39,16 +43,35 @@ func TestQuery(t *testing.T) {
 		And().
 		Compare(comparators).
 		And().
-		BetweenTime(`record_time`, time.Now(), time.Now().Add(24*time.Hour)).
-		Page(2).Size(20).Build()
+		Where(Raw("hello(`satu`)").Compare("like", "tiga")).
+		And().
+		BetweenTime(`record_time`, from, from.Add(24*time.Hour)).
+		Page(2).Size(20)
+	query, values := b.Build()
 
-	t.Log(query)
+	expected := "SELECT test FROM `hello_world` WHERE `Hello` IN (?,?,?)  AND `satu` LIKE ? AND hello(`satu`) LIKE ? AND " +
+		"`record_time` BETWEEN ? AND ? LIMIT 20  OFFSET 20 "
+	if query != expected {
+		t.Fatalf("query:\n got %q\nwant %q", query, expected)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"one", "two", "three", "dua", "tiga", "2024-01-01 00:00:00", "2024-01-02 00:00:00"}) {
+		t.Fatalf("got %#v", values)
+	}
+	if err := b.Err(); err != nil {
+		t.Fatal(err)
+	}
 
-	if len(query) == 0 {
-		t.Fatal("query is empty")
+	for _, key := range []string{"hello(`satu`)", "JSON_EXTRACT(data,'$.a')", "DATE(created_at)", "COUNT(*)"} {
+		b := New().Select("*").From("`hello_world`").Compare([]Condition{{Operator: "=", Key: key, Value: 1}})
+		if !errors.Is(b.Err(), ErrInvalidIdentifier) {
+			t.Errorf("%s: got %v", key, b.Err())
+		}
+	}
+	if err := New().Select("*").From("`t`").Order(OrderBy{Column: "COUNT(*)", Direction: "DESC"}).Err(); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("got %v", err)
 	}
-	if values[0] != "one" {
-		t.Fatal("values is empty")
+	if err := New().Table("`t`").Update(map[string]interface{}{"DATE(a)": 1}).Err(); !errors.Is(err, ErrInvalidIdentifier) {
+		t.Errorf("got %v", err)
 	}
 }
 
@@ -71,15 +94,17 @@ func TestJoin(t *testing.T) {
 func TestUpsert(t *testing.T) {
 	query, values := New().From("`hello_world`", "a").Upsert(map[string]interface{}{
 		"`value`": "name",
-		"`key`":   "`key` + 1.2 ",
-	}).Build()
-	if len(query) == 0 {
-		t.Fatal("query is empty")
+		"`key`":   1.2,
+	}).OnConflict(Inserted("value"), Increment("key", 1.2)).Build()
+	if query != "INSERT INTO `hello_world`(`key`,`value`) VALUES (?,?) ON DUPLICATE KEY UPDATE `value`=VALUES(`value`),`key`=`key` + ?;" {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{1.2, "name", 1.2}) {
+		t.Fatalf("got %#v", values)
 	}
-	t.Log(query, values)
 	query, values = New().From("`hello_world`", "a").Upsert(map[string]interface{}{
 		"`value`": "name",
-		"`key`":   "lower(`key`)",
+		"`key`":   Raw("lower(`key`)"),
 	}).Build()
 	t.Log(query, values)
 	if len(query) == 0 {
@@ -94,3 +119,363 @@ func TestUpsert(t *testing.T) {
 		t.Fatal("query is empty")
 	}
 }
+
+func TestWhere(t *testing.T) {
+	query, values := New().
+		Select("*").
+		From("`post`").
+		Equal("tenant_id", 1).
+		Where(And(
+			Condition{Key: "status", Operator: "=", Value: "active"},
+			Or(
+				Condition{Key: "owner_id", Operator: "=", Value: 2},
+				Not(Condition{Key: "private", Operator: "=", Value: true}),
+			),
+		)).
+		Build()
+	expected := "SELECT * FROM `post` WHERE `tenant_id` = ? AND " +
+		"(`status` = ? AND (`owner_id` = ? OR NOT (`private` = ?)))"
+	if query != expected {
+		t.Fatalf("got %q", query)
+	}
+	if len(values) != 4 || values[0] != 1 || values[1] != "active" || values[2] != 2 || values[3] != true {
+		t.Fatalf("wrong values %v", values)
+	}
+	query, _ = New().
+		Select("*").
+		From("`post`").
+		Where(Or(Condition{Key: "a", Operator: "=", Value: 1})).
+		Where(And(nil, Or())).
+		Build()
+	if query != "SELECT * FROM `post` WHERE `a` = ?" {
+		t.Fatalf("got %q", query)
+	}
+}
+
+func TestMissingOperator(t *testing.T) {
+	query, _ := New().Select("*").From("`post`").Equal("a", 1).Equal("b", 2).Build()
+	if query != "SELECT * FROM `post` WHERE `a` = ? AND `b` = ?" {
+		t.Fatalf("got %q", query)
+	}
+}
+
+func TestDeterministic(t *testing.T) {
+	data := map[string]interface{}{"e": 5, "d": 4, "c": 3, "b": 2, "a": 1}
+	in := map[string]interface{}{"z": []int{1}, "y": []int{2}, "x": []int{3}}
+	for i := 0; i < 20; i++ {
+		query, values := New().Table("`t`").Insert(data).Build()
+		if query != "INSERT INTO `t`(`a`,`b`,`c`,`d`,`e`) VALUES (?,?,?,?,?);" {
+			t.Fatalf("got %q", query)
+		}
+		if values[0] != 1 || values[4] != 5 {
+			t.Fatalf("wrong values %v", values)
+		}
+		query, _ = New().Table("`t`").Update(data).Equal("id", 1).Build()
+		if query != "UPDATE `t` SET `a`=?,`b`=?,`c`=?,`d`=?,`e`=? WHERE `id` = ?" {
+			t.Fatalf("got %q", query)
+		}
+		query, _ = New().Select("*").From("`t`").In(in).Build()
+		if query != "SELECT * FROM `t` WHERE `x` IN (?)  AND `y` IN (?)  AND `z` IN (?) " {
+			t.Fatalf("got %q", query)
+		}
+	}
+}
+
+func TestOrderedMap(t *testing.T) {
+	data := NewOrderedMap().Set("name", "a").Set("age", 1).Set("city", "b").Set("name", "c").Delete("city")
+	query, values := New().Table("`user`").Insert(data.Map(), data.Keys()...).Build()
+	if query != "INSERT INTO `user`(`name`,`age`) VALUES (?,?);" {
+		t.Fatalf("got %q", query)
+	}
+	if values[0] != "c" || values[1] != 1 {
+		t.Fatalf("wrong values %v", values)
+	}
+	data = NewOrderedMap().Set("a", 1).Set("b", nil)
+	query, values = New().Table("`t`").Insert(data.Map(), data.Keys()...).Build()
+	if query != "INSERT INTO `t`(`a`,`b`) VALUES (?,NULL);" || !reflect.DeepEqual(values, []interface{}{1}) {
+		t.Fatalf("got %q %#v", query, values)
+	}
+	in := NewOrderedMap().Set("z", []int{1}).Set("a", []int{2})
+	query, _ = New().Select("*").From("`t`").In(in.Map(), in.Keys()...).Build()
+	if query != "SELECT * FROM `t` WHERE `z` IN (?)  AND `a` IN (?) " {
+		t.Fatalf("got %q", query)
+	}
+}
+
+func TestInsertMany(t *testing.T) {
+	rows := []map[string]interface{}{
+		{"id": 1, "name": "a"},
+		{"id": 2},
+		{"id": 3, "name": "c"},
+	}
+	batches := New().Table("`user`").InsertMany(rows)
+	if len(batches) != 1 {
+		t.Fatalf("expected one batch, got %d", len(batches))
+	}
+	if batches[0].Query != "INSERT INTO `user`(`id`,`name`) VALUES (?,?),(?,?),(?,?);" {
+		t.Fatalf("got %q", batches[0].Query)
+	}
+	if len(batches[0].Args) != 6 || batches[0].Args[3] != nil || batches[0].Args[5] != "c" {
+		t.Fatalf("wrong args %v", batches[0].Args)
+	}
+	batches = New().Table("`user`").UpsertMany(rows[:1], "name")
+	if batches[0].Query != "INSERT INTO `user`(`name`) VALUES (?) ON DUPLICATE KEY UPDATE `name`=VALUES(`name`);" {
+		t.Fatalf("got %q", batches[0].Query)
+	}
+}
+
+func TestInsertManyChunks(t *testing.T) {
+	rows := make([]map[string]interface{}, 0)
+	for i := 0; i < 40000; i++ {
+		rows = append(rows, map[string]interface{}{"a": i, "b": i})
+	}
+	batches := New().Table("`t`").InsertMany(rows)
+	count := 0
+	for _, batch := range batches {
+		if len(batch.Args) > MaxPlaceholders {
+			t.Fatalf("batch exceeds placeholder limit: %d", len(batch.Args))
+		}
+		count += len(batch.Args) / 2
+	}
+	if len(batches) != 2 || count != len(rows) {
+		t.Fatalf("got %d batches holding %d rows", len(batches), count)
+	}
+	rows = []map[string]interface{}{
+		{"a": strings.Repeat("x", 600)},
+		{"a": strings.Repeat("y", 600)},
+		{"a": strings.Repeat("z", 600)},
+	}
+	batches = New().Table("`t`").MaxAllowedPacket(1000).InsertMany(rows)
+	if len(batches) != 3 {
+		t.Fatalf("expected packet limit to split rows, got %d batches", len(batches))
+	}
+}
+
+func TestLock(t *testing.T) {
+	query, _ := New().Select("*").From("`balance`").Equal("user_id", 1).ForUpdate().Build()
+	if query != "SELECT * FROM `balance` WHERE `user_id` = ? FOR UPDATE" {
+		t.Fatalf("got %q", query)
+	}
+	b := New().
+		Select("j.*").
+		From("`job`", "j").
+		Join("`queue`", "q.`id` = j.`queue_id`", "q").
+		Equal("j.`status`", "pending").
+		Size(10).
+		ForUpdate().
+		Of("j").
+		SkipLocked()
+	query, _ = b.Build()
+	if query != "SELECT j.* FROM `job` j JOIN `queue` q  ON q.`id` = j.`queue_id` WHERE j.`status` = ? LIMIT 10  FOR UPDATE OF j SKIP LOCKED" {
+		t.Fatalf("got %q", query)
+	}
+	if !b.Locked() {
+		t.Fatal("expected builder to be locked")
+	}
+	query, _ = New().Select("*").From("`balance`").ForShare().NoWait().Build()
+	if query != "SELECT * FROM `balance` FOR SHARE NOWAIT" {
+		t.Fatalf("got %q", query)
+	}
+}
+
+func TestUpdateJoin(t *testing.T) {
+	query, values := New().
+		Table("`order`", "o").
+		Join("`user`", "u.`id` = o.`user_id`", "u").
+		Update(map[string]interface{}{"o.`status`": "blocked"}).
+		Equal("u.`banned`", true).
+		Build()
+	if query != "UPDATE `order` o JOIN `user` u  ON u.`id` = o.`user_id` SET o.`status`=? WHERE u.`banned` = ?" {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"blocked", true}) {
+		t.Fatalf("wrong values %v", values)
+	}
+	b := New().
+		Table("`session`").
+		Update(map[string]interface{}{"expired": 1}).
+		Equal("user_id", 4).
+		Order(OrderBy{Column: "id", Direction: "ASC"}).
+		Size(100)
+	query, _ = b.Build()
+	if query != "UPDATE `session` SET `expired`=? WHERE `user_id` = ? ORDER BY `id` ASC LIMIT 100 " || b.Err() != nil {
+		t.Fatalf("got %q %v", query, b.Err())
+	}
+}
+
+func TestDeleteJoin(t *testing.T) {
+	query, values := New().Table("`log`").Delete().Equal("level", "debug").Build()
+	if query != "DELETE FROM `log` WHERE `level` = ?" {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"debug"}) {
+		t.Fatalf("wrong values %v", values)
+	}
+	query, _ = New().
+		Table("`log`").
+		Delete().
+		Compare([]Condition{{Key: "created_at", Operator: "<", Value: "2024-01-01"}}).
+		Order(OrderBy{Column: "id", Direction: "ASC"}).
+		Size(1000).
+		Build()
+	if query != "DELETE FROM `log` WHERE `created_at` < ? ORDER BY `id` ASC LIMIT 1000 " {
+		t.Fatalf("got %q", query)
+	}
+	query, values = New().
+		Table("`post`", "p").
+		LeftJoin("`user`", "u.`id` = p.`user_id`", "u").
+		Delete().
+		Statement("u.`id` IS NULL", nil).
+		Build()
+	if query != "DELETE p FROM `post` p LEFT JOIN `user` u  ON u.`id` = p.`user_id` WHERE u.`id` IS NULL" {
+		t.Fatalf("got %q", query)
+	}
+	if len(values) != 0 {
+		t.Fatalf("wrong values %v", values)
+	}
+	query, _ = New().
+		Table("`post`", "p").
+		Join("`comment`", "c.`post_id` = p.`id`", "c").
+		Delete("p", "c").
+		Equal("p.`id`", 1).
+		Build()
+	if query != "DELETE p, c FROM `post` p JOIN `comment` c  ON c.`post_id` = p.`id` WHERE p.`id` = ?" {
+		t.Fatalf("got %q", query)
+	}
+	b := New().Table("`post`", "p").Join("`comment`", "c.`post_id` = p.`id`", "c").Delete().Size(10)
+	b.Build()
+	if b.Err() != ErrMultiTableLimit {
+		t.Fatalf("expected ErrMultiTableLimit, got %v", b.Err())
+	}
+	b = New().Table("`post`").Delete().Size(10).Page(2)
+	if b.Err() != ErrModifierOffset {
+		t.Fatalf("expected ErrModifierOffset before Build, got %v", b.Err())
+	}
+	b.Build()
+	if b.Err() != ErrModifierOffset || b.(*builder).err != nil {
+		t.Fatalf("Build changed the error state: %v", b.(*builder).err)
+	}
+	b = New().Table("`post`").Delete().Size(10)
+	if b.Err() != nil {
+		t.Fatalf("got %v", b.Err())
+	}
+	b.Join("`comment`", "c.`post_id` = `post`.`id`", "c")
+	if b.Err() != ErrMultiTableLimit {
+		t.Fatalf("expected ErrMultiTableLimit, got %v", b.Err())
+	}
+}
+
+func TestRawValues(t *testing.T) {
+	query, values := New().
+		Table("`user`").
+		Update(map[string]interface{}{
+			"name":       "`; DROP TABLE user; --",
+			"score":      Raw("`score` + ?", 1),
+			"updated_by": Col("created_by"),
+		}).
+		Equal("id", 7).
+		Build()
+	if query != "UPDATE `user` SET `name`=?,`score`=`score` + ?,`updated_by`=`created_by` WHERE `id` = ?" {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"`; DROP TABLE user; --", 1, 7}) {
+		t.Fatalf("got %#v", values)
+	}
+
+	query, values = New().Select("*").From("`user`", "u").
+		Compare([]Condition{{Key: "u.updated_at", Operator: ">", Value: Col("u.created_at")}}).Build()
+	if query != "SELECT * FROM `user` u WHERE u.`updated_at` > u.`created_at`" || len(values) != 0 {
+		t.Fatalf("got %q %#v", query, values)
+	}
+
+	LegacyRawStrings = true
+	defer func() { LegacyRawStrings = false }()
+	query, values = New().Select("*").From("`user`").
+		Compare([]Condition{{Key: "name", Operator: "=", Value: "`nickname`"}}).Build()
+	if query != "SELECT * FROM `user` WHERE `name` = `nickname`" || len(values) != 0 {
+		t.Fatalf("got %q %#v", query, values)
+	}
+}
+
+func TestRawInsert(t *testing.T) {
+	query, values := New().Table("`event`").Insert(map[string]interface{}{
+		"name":       "signup",
+		"created_at": Raw("NOW()"),
+		"score":      Raw("? * 2", 4),
+	}).Build()
+	if query != "INSERT INTO `event`(`created_at`,`name`,`score`) VALUES (NOW(),?,? * 2);" {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"signup", 4}) {
+		t.Fatalf("got %#v", values)
+	}
+
+	b := New().Table("`event`")
+	batches := b.InsertMany([]map[string]interface{}{
+		{"name": "a", "created_at": Raw("NOW()")},
+		{"name": "b", "created_at": Raw("NOW() - INTERVAL ? DAY", 1)},
+		{"attrs": JSONSet("attrs", "$.a", 1), "name": "c"},
+	}, "name", "created_at", "attrs")
+	if len(batches) != 1 {
+		t.Fatalf("got %d batches", len(batches))
+	}
+	expected := "INSERT INTO `event`(`name`,`created_at`,`attrs`) VALUES (?,NOW(),?),(?,NOW() - INTERVAL ? DAY,?),(?,?,JSON_SET(`attrs`, ?, ?));"
+	if batches[0].Query != expected {
+		t.Fatalf("query:\n got %q\nwant %q", batches[0].Query, expected)
+	}
+	if !reflect.DeepEqual(batches[0].Args, []interface{}{"a", nil, "b", 1, nil, "c", nil, "$.a", 1}) {
+		t.Fatalf("got %#v", batches[0].Args)
+	}
+
+	b = New().Table("`event`")
+	b.InsertMany([]map[string]interface{}{{"attrs": JSONSet("attrs", "a", 1)}})
+	if b.Err() == nil {
+		t.Fatal("expected the invalid JSON path to be reported")
+	}
+}
+
+func TestColumnsSkipNil(t *testing.T) {
+	data := map[string]interface{}{"name": "a", "email": nil, "note": Null}
+	query, values := New().Table("`user`").Update(data, "name", "email", "note").Equal("id", 1).Build()
+	if query != "UPDATE `user` SET `name`=?,`note`=NULL WHERE `id` = ?" || !reflect.DeepEqual(values, []interface{}{"a", 1}) {
+		t.Fatalf("got %q %#v", query, values)
+	}
+	query, _ = New().Table("`user`").Insert(data, "name", "email").Build()
+	if query != "INSERT INTO `user`(`name`) VALUES (?);" {
+		t.Fatalf("got %q", query)
+	}
+	query, _ = New().Table("`user`").Update(data).Equal("id", 1).Build()
+	if query != "UPDATE `user` SET `email`=NULL,`name`=?,`note`=NULL WHERE `id` = ?" {
+		t.Fatalf("got %q", query)
+	}
+}
+
+func TestOrder(t *testing.T) {
+	b := New().Select("id").Table("`task`").Equal("owner", 3).
+		Order(OrderBy{Column: "status", Fields: []string{"open", "it's done"}}).
+		Order(OrderBy{Column: "id", Direction: "desc"})
+	query, values := b.Build()
+	if query != "SELECT id FROM `task` WHERE `owner` = ? ORDER BY Field(`status`,?,?),`id` DESC" {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{3, "open", "it's done"}) {
+		t.Fatalf("got %#v", values)
+	}
+	if query, _ := b.BuildCount(); strings.Contains(query, "Field") {
+		t.Fatalf("got %q", query)
+	}
+
+	b = New().Select("id").Table("`task`").Order(OrderBy{Column: "id", Direction: "ASC; DROP TABLE task"})
+	if !errors.Is(b.Err(), ErrInvalidDirection) {
+		t.Fatalf("got %v", b.Err())
+	}
+	if query, _ := b.Build(); strings.Contains(query, "DROP") {
+		t.Fatalf("got %q", query)
+	}
+
+	query, values = New().Table("`task`").Update(map[string]interface{}{"done": 1}).
+		Order(OrderBy{Column: "priority", Fields: []string{"high"}}).Size(1).Build()
+	if query != "UPDATE `task` SET `done`=? ORDER BY Field(`priority`,?) LIMIT 1 " || !reflect.DeepEqual(values, []interface{}{1, "high"}) {
+		t.Fatalf("got %q %#v", query, values)
+	}
+}
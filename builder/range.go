@@ -0,0 +1,76 @@
+package builder
+
+import "time"
+
+// formattedTime is a time bound as a DateTimeFormat string, as BetweenTime
+// has always done. It is formatted at Build, once the timezone is known.
+type formattedTime time.Time
+
+func (b *builder) Between(column string, from, to interface{}) Builder {
+	column = b.identifier(column)
+	b.conjunction()
+	b.whereStatement.WriteString(column)
+	b.whereStatement.WriteString(" BETWEEN ")
+	b.values = append(b.values, writeValue(&b.whereStatement, from)...)
+	b.whereStatement.WriteString(" AND ")
+	b.values = append(b.values, writeValue(&b.whereStatement, to)...)
+	return b
+}
+
+// Range matches the half-open interval [from, to), which unlike BETWEEN
+// lets consecutive ranges such as days never share a row. A nil bound
+// leaves that side open.
+func (b *builder) Range(column string, from, to interface{}) Builder {
+	if from == nil && to == nil {
+		return b
+	}
+	column = b.identifier(column)
+	b.conjunction()
+	b.whereStatement.WriteString("(")
+	if from != nil {
+		b.whereStatement.WriteString(column)
+		b.whereStatement.WriteString(" >= ")
+		b.values = append(b.values, writeValue(&b.whereStatement, from)...)
+	}
+	if to != nil {
+		if from != nil {
+			b.whereStatement.WriteString(" AND ")
+		}
+		b.whereStatement.WriteString(column)
+		b.whereStatement.WriteString(" < ")
+		b.values = append(b.values, writeValue(&b.whereStatement, to)...)
+	}
+	b.whereStatement.WriteString(")")
+	return b
+}
+
+// Timezone converts every time.Time bound by the builder to loc, which
+// should be the timezone of the database session. Subqueries convert
+// their values with their own setting.
+func (b *builder) Timezone(loc *time.Location) Builder {
+	b.location = loc
+	return b
+}
+
+func (b *builder) localize(args []interface{}) []interface{} {
+	if args == nil {
+		return nil
+	}
+	values := make([]interface{}, len(args))
+	copy(values, args)
+	for i, value := range values {
+		switch v := value.(type) {
+		case time.Time:
+			if b.location != nil {
+				values[i] = v.In(b.location)
+			}
+		case formattedTime:
+			t := time.Time(v)
+			if b.location != nil {
+				t = t.In(b.location)
+			}
+			values[i] = t.Format(DateTimeFormat)
+		}
+	}
+	return values
+}
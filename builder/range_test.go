@@ -0,0 +1,48 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBetweenAndRange(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, jakarta)
+	to := from.AddDate(0, 1, 0)
+	query, values := New().Select("*").From("`order`").
+		Timezone(time.UTC).
+		Between("total", 10, 20).
+		Range("created_at", from, to).
+		Range("paid_at", nil, to).
+		BetweenTime("updated_at", from, to).
+		Build()
+	expected := "SELECT * FROM `order` WHERE `total` BETWEEN ? AND ? AND (`created_at` >= ? AND `created_at` < ?) AND " +
+		"(`paid_at` < ?) AND `updated_at` BETWEEN ? AND ?"
+	if query != expected {
+		t.Fatalf("query:\n got %q\nwant %q", query, expected)
+	}
+	utcFrom, utcTo := from.In(time.UTC), to.In(time.UTC)
+	if !reflect.DeepEqual(values, []interface{}{10, 20, utcFrom, utcTo, utcTo, "2023-12-31 17:00:00", "2024-01-31 17:00:00"}) {
+		t.Fatalf("got %#v", values)
+	}
+
+	p := QueryParams{BetweenTime: map[string][]time.Time{"createdAt": {from, to}}, Timezone: time.UTC}
+	_, values = FromParams("`order`", p.Clone()).Build()
+	if !reflect.DeepEqual(values, []interface{}{"2023-12-31 17:00:00", "2024-01-31 17:00:00"}) {
+		t.Fatalf("got %#v", values)
+	}
+}
+
+func TestRangeRaw(t *testing.T) {
+	query, values := New().Select("*").From("`order`").
+		Range("created_at", Raw("NOW() - INTERVAL ? DAY", 7), nil).
+		Between("total", Col("min_total"), 3).
+		Build()
+	if query != "SELECT * FROM `order` WHERE (`created_at` >= NOW() - INTERVAL ? DAY) AND `total` BETWEEN `min_total` AND ?" {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{7, 3}) {
+		t.Fatalf("got %#v", values)
+	}
+}
@@ -0,0 +1,117 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LegacyRawStrings restores the former handling of string values holding a
+// backtick, which were inlined as SQL instead of bound. It exists to ease
+// migration to Raw and Col and lets user input inject SQL, so leave it off.
+var LegacyRawStrings = false
+
+// RawExpr is a fragment of SQL inlined as is, with its own arguments. It is
+// the only kind of value the builder does not bind as a parameter.
+type RawExpr struct {
+	sql  string
+	args []interface{}
+	err  error
+}
+
+func Raw(sql string, args ...interface{}) RawExpr {
+	return RawExpr{sql: sql, args: args}
+}
+
+// Col refers to a column, e.g. Update(map[string]interface{}{"a": Col("b")}).
+func Col(name string) RawExpr {
+	return RawExpr{sql: quoteKey(name)}
+}
+
+// Null writes NULL. Insert, Update and Upsert skip nil values of the columns
+// they are given, so clearing a column among them takes Null.
+var Null = RawExpr{sql: "NULL"}
+
+func (r RawExpr) String() string {
+	return r.sql
+}
+
+// Err reports why the expression could not be built, e.g. an invalid path
+// given to JSONSet.
+func (r RawExpr) Err() error {
+	return r.err
+}
+
+// Compare turns the expression into a predicate, e.g.
+// Raw("DATE(`created_at`)").Compare(Eq, day).
+func (r RawExpr) Compare(operator Operator, value interface{}) Expr {
+	return rawCondition{raw: r, operator: operator, value: value}
+}
+
+func (r RawExpr) expr(b *builder) (string, []interface{}) {
+	if r.err != nil {
+		b.fail(r.err)
+	}
+	return r.sql, r.args
+}
+
+type rawCondition struct {
+	raw      RawExpr
+	operator Operator
+	value    interface{}
+}
+
+func (c rawCondition) expr(b *builder) (string, []interface{}) {
+	if c.raw.err != nil {
+		b.fail(c.raw.err)
+	}
+	stmt, values, err := buildComparison(c.raw.sql, c.operator, c.value)
+	if err != nil {
+		b.fail(err)
+	}
+	return stmt, append(append([]interface{}{}, c.raw.args...), values...)
+}
+
+// checkData fails b on the first key of data that is not a column and on
+// the first raw value that could not be built.
+func (b *builder) checkData(data map[string]interface{}, columns []string) {
+	for _, key := range append(sortedKeys(data), columns...) {
+		if !validIdentifier(key) {
+			b.fail(fmt.Errorf("%w: %q", ErrInvalidIdentifier, key))
+			return
+		}
+	}
+	for _, key := range sortedKeys(data) {
+		if raw, ok := data[key].(RawExpr); ok && raw.err != nil {
+			b.fail(raw.err)
+			return
+		}
+	}
+}
+
+// rawValue reports whether value is to be inlined rather than bound.
+func rawValue(value interface{}) (RawExpr, bool) {
+	switch v := value.(type) {
+	case RawExpr:
+		return v, true
+	case string:
+		if LegacyRawStrings && strings.Contains(v, "`") {
+			return Raw(v), true
+		}
+	}
+	return RawExpr{}, false
+}
+
+// writeValue writes value as a placeholder, NULL or an inlined Raw
+// expression and returns the arguments it needs.
+func writeValue(s *strings.Builder, value interface{}) []interface{} {
+	if value == nil {
+		s.WriteString("NULL")
+		return nil
+	}
+	if raw, ok := rawValue(value); ok {
+		s.WriteString(raw.sql)
+		return raw.args
+	}
+	s.WriteString("?")
+	return []interface{}{value}
+}
@@ -0,0 +1,84 @@
+package builder
+
+import "strings"
+
+// likeEscape is the escape character of Contains, StartsWith and EndsWith.
+// It is not a backslash so patterns behave the same with or without the
+// NO_BACKSLASH_ESCAPES SQL mode.
+const likeEscape = "!"
+
+var likeReplacer = strings.NewReplacer(likeEscape, likeEscape+likeEscape, "%", likeEscape+"%", "_", likeEscape+"_")
+
+type like struct {
+	column  string
+	pattern string
+}
+
+func (l like) expr(b *builder) (string, []interface{}) {
+	return b.identifier(l.column) + " LIKE ? ESCAPE '" + likeEscape + "'", []interface{}{l.pattern}
+}
+
+// Contains matches column containing value, taking any % or _ in value
+// literally.
+func Contains(column, value string) Expr {
+	return like{column: column, pattern: "%" + likeReplacer.Replace(value) + "%"}
+}
+
+func StartsWith(column, value string) Expr {
+	return like{column: column, pattern: likeReplacer.Replace(value) + "%"}
+}
+
+func EndsWith(column, value string) Expr {
+	return like{column: column, pattern: "%" + likeReplacer.Replace(value)}
+}
+
+// MatchMode is the search modifier of a FULLTEXT match.
+type MatchMode string
+
+const (
+	NaturalLanguageMode MatchMode = "IN NATURAL LANGUAGE MODE"
+	BooleanMode         MatchMode = "IN BOOLEAN MODE"
+	QueryExpansionMode  MatchMode = "WITH QUERY EXPANSION"
+)
+
+// Fulltext is a MATCH ... AGAINST search over the columns of a FULLTEXT
+// index. It filters as an Expr and is scored with SelectMatch:
+//
+//	m := Match([]string{"title", "body"}, q, BooleanMode)
+//	New().Select("*").SelectMatch(m, "score").From("`post`").Where(m).
+//		Order(OrderBy{Column: "score", Direction: "DESC"})
+type Fulltext struct {
+	columns []string
+	query   string
+	mode    MatchMode
+}
+
+func Match(columns []string, query string, mode MatchMode) Fulltext {
+	return Fulltext{columns: columns, query: query, mode: mode}
+}
+
+func (m Fulltext) expr(b *builder) (string, []interface{}) {
+	var s strings.Builder
+	s.WriteString("MATCH (")
+	for i, column := range m.columns {
+		if i > 0 {
+			s.WriteString(",")
+		}
+		s.WriteString(b.identifier(column))
+	}
+	s.WriteString(") AGAINST (?")
+	if m.mode != "" {
+		s.WriteString(" ")
+		s.WriteString(string(m.mode))
+	}
+	s.WriteString(")")
+	return s.String(), []interface{}{m.query}
+}
+
+// SelectMatch selects the relevance score of m as alias.
+func (b *builder) SelectMatch(m Fulltext, alias string) Builder {
+	stmt, values := m.expr(b)
+	b.Select(stmt + " AS " + quoteKey(alias))
+	b.selectValues = append(b.selectValues, values...)
+	return b
+}
@@ -0,0 +1,36 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLikeHelpers(t *testing.T) {
+	query, values := New().Select("*").From("`post`").
+		Where(Or(Contains("title", "50%_off!"), StartsWith("slug", "a_"), EndsWith("email", "@x.io"))).
+		Build()
+	expected := "SELECT * FROM `post` WHERE (`title` LIKE ? ESCAPE '!' OR `slug` LIKE ? ESCAPE '!' OR `email` LIKE ? ESCAPE '!')"
+	if query != expected {
+		t.Fatalf("query:\n got %q\nwant %q", query, expected)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"%50!%!_off!!%", "a!_%", "%@x.io"}) {
+		t.Fatalf("got %#v", values)
+	}
+}
+
+func TestMatch(t *testing.T) {
+	m := Match([]string{"title", "p.body"}, "+go -java", BooleanMode)
+	query, values := New().Select("p.`id`").SelectMatch(m, "score").From("`post`", "p").
+		Equal("p.status", "published").
+		Where(m).
+		Order(OrderBy{Column: "score", Direction: "DESC"}).
+		Build()
+	expected := "SELECT p.`id`,MATCH (`title`,p.`body`) AGAINST (? IN BOOLEAN MODE) AS `score` FROM `post` p " +
+		"WHERE p.`status` = ? AND MATCH (`title`,p.`body`) AGAINST (? IN BOOLEAN MODE) ORDER BY `score` DESC"
+	if query != expected {
+		t.Fatalf("query:\n got %q\nwant %q", query, expected)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"+go -java", "published", "+go -java"}) {
+		t.Fatalf("got %#v", values)
+	}
+}
@@ -0,0 +1,184 @@
+package builder
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrNotStruct    = errors.New("value is not a struct")
+	ErrNoPrimaryKey = errors.New("no primary key")
+)
+
+// structField describes one column of a struct, as declared by its db tag:
+//
+//	ID        int64     `db:"id,pk"`
+//	Name      string    `db:"name"`
+//	Note      *string   `db:"note,omitempty"`
+//	CreatedAt time.Time `db:"created_at,readonly"`
+//
+// Fields without a tag are named through ResolveColumnNameWithoutBacktick
+// and fields tagged "-" are ignored.
+type structField struct {
+	column    string
+	index     []int
+	omitempty bool
+	readonly  bool
+	pk        bool
+}
+
+var structCache sync.Map
+
+func structFields(t reflect.Type) []structField {
+	if cached, ok := structCache.Load(t); ok {
+		return cached.([]structField)
+	}
+	fields := collectStructFields(t, nil)
+	structCache.Store(t, fields)
+	return fields
+}
+
+func collectStructFields(t reflect.Type, parent []int) []structField {
+	fields := make([]structField, 0)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag, tagged := field.Tag.Lookup("db")
+		if tag == "-" {
+			continue
+		}
+		index := make([]int, 0, len(parent)+1)
+		index = append(index, parent...)
+		index = append(index, i)
+		options := strings.Split(tag, ",")
+		if field.Anonymous && options[0] == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				fields = append(fields, collectStructFields(embedded, index)...)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		item := structField{column: options[0], index: index}
+		if !tagged || item.column == "" {
+			item.column = ResolveColumnNameWithoutBacktick(field.Name)
+		}
+		for _, option := range options[1:] {
+			switch strings.TrimSpace(option) {
+			case "omitempty":
+				item.omitempty = true
+			case "readonly":
+				item.readonly = true
+			case "pk":
+				item.pk = true
+			}
+		}
+		fields = append(fields, item)
+	}
+	return fields
+}
+
+// structData extracts the writable columns of v in declaration order,
+// together with the primary key columns and their values.
+func structData(v interface{}) (map[string]interface{}, []string, []structField, map[string]interface{}, error) {
+	value := reflect.ValueOf(v)
+	for value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			return nil, nil, nil, nil, ErrNotStruct
+		}
+		value = value.Elem()
+	}
+	if value.Kind() != reflect.Struct {
+		return nil, nil, nil, nil, ErrNotStruct
+	}
+	fields := structFields(value.Type())
+	data := make(map[string]interface{})
+	columns := make([]string, 0, len(fields))
+	keys := make(map[string]interface{})
+	for _, field := range fields {
+		tmp, err := value.FieldByIndexErr(field.index)
+		if err != nil {
+			continue
+		}
+		var item interface{}
+		if tmp.Kind() != reflect.Ptr || !tmp.IsNil() {
+			item = tmp.Interface()
+		}
+		keys[field.column] = item
+		if field.readonly || field.omitempty && tmp.IsZero() {
+			continue
+		}
+		if item == nil {
+			item = Null
+		}
+		data[field.column] = item
+		columns = append(columns, field.column)
+	}
+	return data, columns, fields, keys, nil
+}
+
+func (b *builder) InsertStruct(v interface{}) Builder {
+	data, columns, _, _, err := structData(v)
+	if err != nil {
+		b.fail(err)
+		return b
+	}
+	return b.Insert(data, columns...)
+}
+
+func (b *builder) UpsertStruct(v interface{}) Builder {
+	data, columns, _, _, err := structData(v)
+	if err != nil {
+		b.fail(err)
+		return b
+	}
+	return b.Upsert(data, columns...)
+}
+
+// UpdateStruct sets every writable column of v and filters on pk, or on the
+// fields tagged pk when no column is given.
+func (b *builder) UpdateStruct(v interface{}, pk ...string) Builder {
+	data, columns, fields, values, err := structData(v)
+	if err != nil {
+		b.fail(err)
+		return b
+	}
+	if len(pk) == 0 {
+		for _, field := range fields {
+			if field.pk {
+				pk = append(pk, field.column)
+			}
+		}
+	}
+	if len(pk) == 0 {
+		b.fail(ErrNoPrimaryKey)
+		return b
+	}
+	keys := make(map[string]bool)
+	for _, column := range pk {
+		if _, ok := values[column]; !ok {
+			b.fail(ErrNoPrimaryKey)
+			return b
+		}
+		keys[column] = true
+	}
+	set := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if keys[column] {
+			delete(data, column)
+		} else {
+			set = append(set, column)
+		}
+	}
+	b.Update(data, set...)
+	for _, column := range pk {
+		b.Equal(column, values[column])
+	}
+	return b
+}
@@ -0,0 +1,57 @@
+package builder
+
+import (
+	"testing"
+	"time"
+)
+
+type auditFields struct {
+	CreatedAt time.Time `db:"created_at,readonly"`
+	UpdatedBy string
+}
+
+type account struct {
+	ID      int64   `db:"id,pk"`
+	Name    string  `db:"name"`
+	Note    *string `db:"note,omitempty"`
+	Email   *string `db:"email"`
+	Ignored string  `db:"-"`
+	auditFields
+}
+
+func TestStruct(t *testing.T) {
+	v := account{ID: 7, Name: "a", auditFields: auditFields{UpdatedBy: "system"}}
+	query, values := New().Table("`account`").InsertStruct(&v).Build()
+	if query != "INSERT INTO `account`(`id`,`name`,`email`,`updated_by`) VALUES (?,?,NULL,?);" {
+		t.Fatalf("got %q", query)
+	}
+	if len(values) != 3 || values[0] != int64(7) || values[2] != "system" {
+		t.Fatalf("wrong values %v", values)
+	}
+	query, values = New().Table("`account`").UpdateStruct(v).Build()
+	if query != "UPDATE `account` SET `name`=?,`email`=NULL,`updated_by`=? WHERE `id` = ?" {
+		t.Fatalf("got %q", query)
+	}
+	if len(values) != 3 || values[2] != int64(7) {
+		t.Fatalf("wrong values %v", values)
+	}
+	query, _ = New().Table("`account`").UpdateStruct(v, "name").Build()
+	if query != "UPDATE `account` SET `id`=?,`email`=NULL,`updated_by`=? WHERE `name` = ?" {
+		t.Fatalf("got %q", query)
+	}
+	query, values = New().Table("`account`").UpdateStruct(v, "updated_by").Build()
+	if query != "UPDATE `account` SET `id`=?,`name`=?,`email`=NULL WHERE `updated_by` = ?" || values[2] != "system" {
+		t.Fatalf("got %q %v", query, values)
+	}
+	query, _ = New().Table("`account`").UpsertStruct(v).Build()
+	if query != "INSERT INTO `account`(`id`,`name`,`email`,`updated_by`) VALUES (?,?,NULL,?) "+
+		"ON DUPLICATE KEY UPDATE `id`=VALUES(`id`),`name`=VALUES(`name`),`email`=NULL,`updated_by`=VALUES(`updated_by`);" {
+		t.Fatalf("got %q", query)
+	}
+	if err := New().Table("`account`").InsertStruct(1).Err(); err != ErrNotStruct {
+		t.Fatalf("expected ErrNotStruct, got %v", err)
+	}
+	if err := New().Table("`account`").UpdateStruct(v, "unknown").Err(); err != ErrNoPrimaryKey {
+		t.Fatalf("expected ErrNoPrimaryKey, got %v", err)
+	}
+}
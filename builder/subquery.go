@@ -0,0 +1,82 @@
+package builder
+
+func (b *builder) SelectSubquery(other Builder, alias string) Builder {
+	stmt, values := b.embed(other)
+	b.Select("(" + stmt + ") AS " + alias)
+	b.selectValues = append(b.selectValues, values...)
+	return b
+}
+
+func (b *builder) FromSubquery(other Builder, alias string) Builder {
+	return b.subquery("FROM", other, "", alias)
+}
+
+func (b *builder) JoinSubquery(other Builder, on string, alias string) Builder {
+	return b.subquery("JOIN", other, on, alias)
+}
+
+func (b *builder) LeftJoinSubquery(other Builder, on string, alias string) Builder {
+	return b.subquery("LEFT JOIN", other, on, alias)
+}
+
+func (b *builder) subquery(operator string, other Builder, on string, alias string) Builder {
+	stmt, values := b.embed(other)
+	tmp := make(map[string]string)
+	tmp["table"] = "(" + stmt + ")"
+	tmp["operator"] = operator
+	tmp["alias"] = alias
+	if on != "" {
+		tmp["on"] = on
+	}
+	if len(values) > 0 {
+		if b.sourceValues == nil {
+			b.sourceValues = make(map[int][]interface{})
+		}
+		b.sourceValues[len(b.source)] = values
+	}
+	b.source = append(b.source, tmp)
+	return b
+}
+
+func (b *builder) InSubquery(column string, other Builder) Builder {
+	column = b.identifier(column)
+	stmt, values := b.embed(other)
+	b.conjunction()
+	b.whereStatement.WriteString(column)
+	b.whereStatement.WriteString(" IN (")
+	b.whereStatement.WriteString(stmt)
+	b.whereStatement.WriteString(")")
+	b.values = append(b.values, values...)
+	return b
+}
+
+func (b *builder) NotExists(other Builder, condition Condition) Builder {
+	return b.exists("NOT EXISTS", other, condition)
+}
+
+// exists correlates other with condition and nests it, rendering a clone so
+// the caller's builder is left untouched.
+func (b *builder) exists(operator string, other Builder, condition Condition) Builder {
+	_other := other.(*builder)
+	stmt, values, err := buildComparison(b.identifier(condition.Key), condition.Operator, condition.Value)
+	if err != nil {
+		b.fail(err)
+	}
+	values = append(values, _other.values...)
+	inner := _other.clone()
+	inner.whereStatement.Reset()
+	inner.whereStatement.WriteString(stmt)
+	if _other.whereStatement.Len() > 0 {
+		inner.whereStatement.WriteString(" AND ")
+		inner.whereStatement.WriteString(_other.whereStatement.String())
+	}
+	inner.values = values
+	innerStatement, innerValues := b.embed(inner)
+	b.conjunction()
+	b.whereStatement.WriteString(operator)
+	b.whereStatement.WriteString(" (")
+	b.whereStatement.WriteString(innerStatement)
+	b.whereStatement.WriteString(")")
+	b.values = append(b.values, innerValues...)
+	return b
+}
@@ -0,0 +1,49 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSubquery(t *testing.T) {
+	latest := New().Select("MAX(`id`)").From("`comment`", "c").Statement("c.`post_id` = p.`id`", nil).And().Equal("visible", 1)
+	totals := New().Select("`user_id`,COUNT(*) AS total").From("`order`").Equal("status", "paid").Group("user_id")
+	active := New().Select("`id`").From("`user`").Equal("active", true)
+	query, values := New().
+		Select("p.*").
+		SelectSubquery(latest, "latest_comment").
+		FromSubquery(New().Select("*").From("`post`").Equal("deleted", 0), "p").
+		JoinSubquery(totals, "t.`user_id` = p.`user_id`", "t").
+		InSubquery("p.`user_id`", active).
+		Build()
+	expected := "SELECT p.*,(SELECT MAX(`id`) FROM `comment` c WHERE c.`post_id` = p.`id` AND `visible` = ?) AS latest_comment " +
+		"FROM (SELECT * FROM `post` WHERE `deleted` = ?) p " +
+		"JOIN (SELECT `user_id`,COUNT(*) AS total FROM `order` WHERE `status` = ? GROUP BY `user_id`) t  ON t.`user_id` = p.`user_id` " +
+		"WHERE p.`user_id` IN (SELECT `id` FROM `user` WHERE `active` = ?)"
+	if query != expected {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{1, 0, "paid", true}) {
+		t.Fatalf("wrong values %v", values)
+	}
+}
+
+func TestNotExists(t *testing.T) {
+	inner := New().Select("1").From("`ban`", "b").Equal("active", true)
+	before, _ := inner.Build()
+	query, values := New().
+		Select("*").
+		From("`user`", "u").
+		Equal("tenant_id", 3).
+		NotExists(inner, Condition{Key: "b.user_id", Operator: "=", Value: Col("u.id")}).
+		Build()
+	if query != "SELECT * FROM `user` u WHERE `tenant_id` = ? AND NOT EXISTS (SELECT 1 FROM `ban` b WHERE b.`user_id` = u.`id` AND `active` = ?)" {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{3, true}) {
+		t.Fatalf("wrong values %v", values)
+	}
+	if after, _ := inner.Build(); after != before {
+		t.Fatalf("inner builder was mutated: %q", after)
+	}
+}
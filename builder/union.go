@@ -0,0 +1,66 @@
+package builder
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrCompoundClause is reported for a clause set on a Union, UnionAll or
+// Intersect builder that the combined result cannot take; filter the parts
+// instead, or select from the compound through FromSubquery.
+var ErrCompoundClause = errors.New("clause is not supported on a compound query")
+
+// Union combines the results of parts, dropping duplicates. Order, Page and
+// Size on the returned builder apply to the combined result.
+func Union(parts ...Builder) Builder {
+	return &builder{compound: parts, setOperator: " UNION "}
+}
+
+// UnionAll is Union keeping duplicates.
+func UnionAll(parts ...Builder) Builder {
+	return &builder{compound: parts, setOperator: " UNION ALL "}
+}
+
+// Intersect keeps the rows found in every one of parts.
+func Intersect(parts ...Builder) Builder {
+	return &builder{compound: parts, setOperator: " INTERSECT "}
+}
+
+// compoundClauseErr reports the first clause a compound builder would drop,
+// as only ORDER BY, LIMIT and OFFSET apply to the combined result.
+func (b *builder) compoundClauseErr() error {
+	clauses := []struct {
+		name string
+		set  bool
+	}{
+		{"SELECT", b.selectStatement.Len() > 0 || b.distinct},
+		{"FROM", len(b.source) > 0},
+		{"WHERE", b.whereStatement.Len() > 0},
+		{"GROUP BY", b.groupStatement.Len() > 0},
+		{"HAVING", b.havingStatement.Len() > 0},
+		{"WINDOW", b.windowStatement.Len() > 0},
+		{"lock", b.lock != ""},
+	}
+	for _, clause := range clauses {
+		if clause.set {
+			return fmt.Errorf("%w: %s", ErrCompoundClause, clause.name)
+		}
+	}
+	return nil
+}
+
+func (b *builder) buildCompound(query *strings.Builder) []interface{} {
+	var values []interface{}
+	for i, part := range b.compound {
+		if i > 0 {
+			query.WriteString(b.setOperator)
+		}
+		stmt, tmp := nested(part)
+		query.WriteString("(")
+		query.WriteString(stmt)
+		query.WriteString(")")
+		values = append(values, tmp...)
+	}
+	return values
+}
@@ -0,0 +1,53 @@
+package builder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestUnion(t *testing.T) {
+	users := New().Select("`id`,`name`").From("`user`").Equal("active", true)
+	admins := New().Select("`id`,`name`").From("`admin`").Equal("level", 2)
+	query, values := Union(users, admins).
+		Order(OrderBy{Column: "name", Direction: "ASC"}).
+		Size(10).
+		Page(2).
+		Build()
+	expected := "(SELECT `id`,`name` FROM `user` WHERE `active` = ?) UNION " +
+		"(SELECT `id`,`name` FROM `admin` WHERE `level` = ?) ORDER BY `name` ASC LIMIT 10  OFFSET 10 "
+	if query != expected {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{true, 2}) {
+		t.Fatalf("wrong values %v", values)
+	}
+	query, _ = UnionAll(users, admins).Build()
+	if query != "(SELECT `id`,`name` FROM `user` WHERE `active` = ?) UNION ALL (SELECT `id`,`name` FROM `admin` WHERE `level` = ?)" {
+		t.Fatalf("got %q", query)
+	}
+	query, _ = Intersect(users, admins).Build()
+	if query != "(SELECT `id`,`name` FROM `user` WHERE `active` = ?) INTERSECT (SELECT `id`,`name` FROM `admin` WHERE `level` = ?)" {
+		t.Fatalf("got %q", query)
+	}
+}
+
+func TestUnionClause(t *testing.T) {
+	a := New().Select("id").From("`a`")
+	c := New().Select("id").From("`c`")
+	cases := map[string]func(Builder) Builder{
+		"where":  func(b Builder) Builder { return b.Equal("x", 1) },
+		"join":   func(b Builder) Builder { return b.Join("`d`", "`d`.`id` = `id`") },
+		"group":  func(b Builder) Builder { return b.Group("x") },
+		"having": func(b Builder) Builder { return b.Having(Raw("COUNT(*) > ?", 1)) },
+		"lock":   func(b Builder) Builder { return b.ForUpdate() },
+	}
+	for name, apply := range cases {
+		if err := apply(Union(a, c)).Err(); !errors.Is(err, ErrCompoundClause) {
+			t.Fatalf("%s: got %v", name, err)
+		}
+	}
+	if err := Union(a, c).Order(OrderBy{Column: "id"}).Size(5).Err(); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,167 @@
+package builder
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrNoConflictTarget = errors.New("conflict target is required to update on conflict")
+
+const (
+	assignInserted = iota
+	assignValue
+	assignIncrement
+)
+
+// Assignment is one column update applied when an upsert hits an existing
+// row.
+type Assignment struct {
+	column string
+	kind   int
+	value  interface{}
+}
+
+// Inserted sets column to the value the upsert tried to insert.
+func Inserted(column string) Assignment {
+	return Assignment{column: column, kind: assignInserted}
+}
+
+// Assign sets column to value, which may be a Raw expression.
+func Assign(column string, value interface{}) Assignment {
+	return Assignment{column: column, kind: assignValue, value: value}
+}
+
+// Increment adds delta to the current value of column.
+func Increment(column string, delta interface{}) Assignment {
+	return Assignment{column: column, kind: assignIncrement, value: delta}
+}
+
+type conflict struct {
+	assignments []Assignment
+	nothing     bool
+	alias       string
+	target      []string
+	dialect     Dialect
+	table       string
+}
+
+// OnConflict replaces the default upsert behaviour, which updates every
+// inserted column with its inserted value, with assignments.
+func (b *builder) OnConflict(assignments ...Assignment) Builder {
+	b.conflict.assignments = append(b.conflict.assignments, assignments...)
+	return b
+}
+
+// DoNothing keeps existing rows untouched on conflict.
+func (b *builder) DoNothing() Builder {
+	b.conflict.nothing = true
+	return b
+}
+
+// RowAlias names the inserted row so Inserted renders alias.col instead of
+// the VALUES(col) function deprecated since MySQL 8.0.20.
+func (b *builder) RowAlias(alias string) Builder {
+	b.conflict.alias = alias
+	return b
+}
+
+// ConflictTarget lists the unique columns ON CONFLICT refers to, required
+// by PostgreSQL and SQLite to update on conflict.
+func (b *builder) ConflictTarget(columns ...string) Builder {
+	b.conflict.target = append(b.conflict.target, columns...)
+	return b
+}
+
+func (b *builder) conflictStrategy() conflict {
+	strategy := b.conflict
+	strategy.dialect = b.dialect
+	strategy.table = b.source[0]["table"]
+	return strategy
+}
+
+// buildConflictClause renders what follows the VALUES list of an upsert.
+// keys are the quoted inserted columns and raws their raw values, used for
+// the default update of every inserted column.
+func buildConflictClause(strategy conflict, keys []string, raws map[string]RawExpr) (string, []interface{}, error) {
+	var clause strings.Builder
+	var values []interface{}
+	assignments := strategy.assignments
+	if len(assignments) == 0 {
+		for _, key := range keys {
+			if raw, ok := raws[key]; ok {
+				assignments = append(assignments, Assign(key, raw))
+			} else {
+				assignments = append(assignments, Inserted(key))
+			}
+		}
+	}
+	if strategy.dialect == PostgreSQL || strategy.dialect == SQLite {
+		clause.WriteString(" ON CONFLICT")
+		if len(strategy.target) > 0 {
+			clause.WriteString(" (")
+			for i, column := range strategy.target {
+				if i > 0 {
+					clause.WriteString(",")
+				}
+				clause.WriteString(quoteKey(column))
+			}
+			clause.WriteString(")")
+		}
+		if strategy.nothing {
+			clause.WriteString(" DO NOTHING")
+			return clause.String(), nil, nil
+		}
+		if len(strategy.target) == 0 {
+			return clause.String(), nil, ErrNoConflictTarget
+		}
+		clause.WriteString(" DO UPDATE SET ")
+	} else {
+		if strategy.alias != "" {
+			clause.WriteString(" AS ")
+			clause.WriteString(strategy.alias)
+		}
+		clause.WriteString(" ON DUPLICATE KEY UPDATE ")
+		if strategy.nothing && len(keys) > 0 {
+			clause.WriteString(keys[0])
+			clause.WriteString("=")
+			clause.WriteString(keys[0])
+			return clause.String(), nil, nil
+		}
+	}
+	for i, assignment := range assignments {
+		if i > 0 {
+			clause.WriteString(",")
+		}
+		key := quoteKey(assignment.column)
+		clause.WriteString(key)
+		clause.WriteString("=")
+		switch assignment.kind {
+		case assignInserted:
+			switch {
+			case strategy.dialect == PostgreSQL || strategy.dialect == SQLite:
+				clause.WriteString("EXCLUDED.")
+				clause.WriteString(key)
+			case strategy.alias != "":
+				clause.WriteString(strategy.alias)
+				clause.WriteString(".")
+				clause.WriteString(key)
+			default:
+				clause.WriteString("VALUES(")
+				clause.WriteString(key)
+				clause.WriteString(")")
+			}
+		case assignIncrement:
+			if strategy.dialect == PostgreSQL || strategy.dialect == SQLite {
+				// EXCLUDED has the column too, so the existing row is named.
+				clause.WriteString(strategy.table)
+				clause.WriteString(".")
+			}
+			clause.WriteString(key)
+			clause.WriteString(" + ")
+			values = append(values, writeValue(&clause, assignment.value)...)
+		default:
+			values = append(values, writeValue(&clause, assignment.value)...)
+		}
+	}
+	return clause.String(), values, nil
+}
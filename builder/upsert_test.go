@@ -0,0 +1,113 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUpsertStrategy(t *testing.T) {
+	data := map[string]interface{}{"id": 1, "name": "a", "visits": 1}
+	cases := []struct {
+		name   string
+		build  func(Builder) Builder
+		query  string
+		values []interface{}
+		err    error
+	}{
+		{
+			name:   "default",
+			build:  func(b Builder) Builder { return b },
+			query:  "INSERT INTO `t`(`id`,`name`,`visits`) VALUES (?,?,?) ON DUPLICATE KEY UPDATE `id`=VALUES(`id`),`name`=VALUES(`name`),`visits`=VALUES(`visits`);",
+			values: []interface{}{1, "a", 1},
+		},
+		{
+			name: "chosen columns",
+			build: func(b Builder) Builder {
+				return b.OnConflict(Inserted("name"), Increment("visits", 1), Assign("updated_at", Raw("NOW()")))
+			},
+			query:  "INSERT INTO `t`(`id`,`name`,`visits`) VALUES (?,?,?) ON DUPLICATE KEY UPDATE `name`=VALUES(`name`),`visits`=`visits` + ?,`updated_at`=NOW();",
+			values: []interface{}{1, "a", 1, 1},
+		},
+		{
+			name:   "row alias",
+			build:  func(b Builder) Builder { return b.RowAlias("new").OnConflict(Inserted("name")) },
+			query:  "INSERT INTO `t`(`id`,`name`,`visits`) VALUES (?,?,?) AS new ON DUPLICATE KEY UPDATE `name`=new.`name`;",
+			values: []interface{}{1, "a", 1},
+		},
+		{
+			name:   "do nothing",
+			build:  func(b Builder) Builder { return b.DoNothing() },
+			query:  "INSERT INTO `t`(`id`,`name`,`visits`) VALUES (?,?,?) ON DUPLICATE KEY UPDATE `id`=`id`;",
+			values: []interface{}{1, "a", 1},
+		},
+		{
+			name: "postgresql",
+			build: func(b Builder) Builder {
+				return b.Dialect(PostgreSQL).ConflictTarget("id").OnConflict(Inserted("name"), Increment("visits", 1))
+			},
+			query:  `INSERT INTO "t"("id","name","visits") VALUES ($1,$2,$3) ON CONFLICT ("id") DO UPDATE SET "name"=EXCLUDED."name","visits"="t"."visits" + $4;`,
+			values: []interface{}{1, "a", 1, 1},
+		},
+		{
+			name: "sqlite increment",
+			build: func(b Builder) Builder {
+				return b.Dialect(SQLite).ConflictTarget("id").OnConflict(Increment("visits", 1))
+			},
+			query:  `INSERT INTO "t"("id","name","visits") VALUES (?,?,?) ON CONFLICT ("id") DO UPDATE SET "visits"="t"."visits" + ?;`,
+			values: []interface{}{1, "a", 1, 1},
+		},
+		{
+			name:   "sqlite do nothing",
+			build:  func(b Builder) Builder { return b.Dialect(SQLite).DoNothing() },
+			query:  `INSERT INTO "t"("id","name","visits") VALUES (?,?,?) ON CONFLICT DO NOTHING;`,
+			values: []interface{}{1, "a", 1},
+		},
+		{
+			name:   "postgresql without target",
+			build:  func(b Builder) Builder { return b.Dialect(PostgreSQL) },
+			query:  `INSERT INTO "t"("id","name","visits") VALUES ($1,$2,$3) ON CONFLICT;`,
+			values: []interface{}{1, "a", 1},
+			err:    ErrNoConflictTarget,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := c.build(New().Table("`t`")).Upsert(data)
+			query, values := b.Build()
+			if query != c.query {
+				t.Fatalf("got %q", query)
+			}
+			if !reflect.DeepEqual(values, c.values) {
+				t.Fatalf("wrong values %v", values)
+			}
+			if b.Err() != c.err {
+				t.Fatalf("wrong error %v", b.Err())
+			}
+		})
+	}
+}
+
+func TestUpsertRaw(t *testing.T) {
+	query, values := New().Table("`t`").Upsert(map[string]interface{}{
+		"id":    1,
+		"total": Raw("`total` + ?", 5),
+	}).Build()
+	if query != "INSERT INTO `t`(`id`,`total`) VALUES (?,`total` + ?) ON DUPLICATE KEY UPDATE `id`=VALUES(`id`),`total`=`total` + ?;" {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{1, 5, 5}) {
+		t.Fatalf("wrong values %v", values)
+	}
+}
+
+func TestUpsertManyStrategy(t *testing.T) {
+	rows := []map[string]interface{}{{"id": 1, "hits": 2}, {"id": 2, "hits": 3}}
+	batches := New().Table("`t`").RowAlias("new").OnConflict(Assign("hits", Raw("`hits` + new.`hits`"))).UpsertMany(rows)
+	if batches[0].Query != "INSERT INTO `t`(`hits`,`id`) VALUES (?,?),(?,?) AS new ON DUPLICATE KEY UPDATE `hits`=`hits` + new.`hits`;" {
+		t.Fatalf("got %q", batches[0].Query)
+	}
+	batches = New().Table("`t`").OnConflict(Increment("hits", 1)).UpsertMany(rows)
+	if !reflect.DeepEqual(batches[0].Args, []interface{}{2, 1, 3, 2, 1}) {
+		t.Fatalf("wrong args %v", batches[0].Args)
+	}
+}
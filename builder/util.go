@@ -11,32 +11,13 @@ import (
 
 type Scanner func(args ...interface{}) error
 
+// ResolveColumnName turns a camel case name into a quoted snake case column,
+// e.g. "post.userId" into "post.`user_id`".
 func ResolveColumnName(column string) string {
-	var builder strings.Builder
-	var prev rune
-	caser := cases.Title(language.Und, cases.NoLower)
-	column = caser.String(column)
 	if strings.Contains(column, "`") {
-		return column
-	}
-	containsDot := strings.Contains(column, ".")
-	if !containsDot {
-		builder.WriteRune('`')
-	}
-	for _, curr := range column {
-		if prev >= 'a' && prev <= 'z' && curr >= 'A' && curr <= 'Z' {
-			builder.WriteString("_")
-			builder.WriteRune(unicode.ToLower(curr))
-		} else {
-			builder.WriteRune(unicode.ToLower(curr))
-		}
-		if curr == '.' {
-			builder.WriteRune('`')
-		}
-		prev = curr
+		return quoteKey(column)
 	}
-	builder.WriteRune('`')
-	return builder.String()
+	return quoteKey(ResolveColumnNameWithoutBacktick(column))
 }
 func ResolveColumnNameWithoutBacktick(column string) string {
 	var builder strings.Builder
@@ -0,0 +1,224 @@
+package builder
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	UnboundedPreceding = "UNBOUNDED PRECEDING"
+	UnboundedFollowing = "UNBOUNDED FOLLOWING"
+	CurrentRow         = "CURRENT ROW"
+)
+
+func Preceding(n int) string {
+	return fmt.Sprintf("%d PRECEDING", n)
+}
+
+func Following(n int) string {
+	return fmt.Sprintf("%d FOLLOWING", n)
+}
+
+// Frame bounds the rows a window function sees around the current row.
+type Frame struct {
+	Unit  string
+	Start string
+	End   string
+}
+
+func RowsBetween(start, end string) *Frame {
+	return &Frame{Unit: "ROWS", Start: start, End: end}
+}
+
+func RangeBetween(start, end string) *Frame {
+	return &Frame{Unit: "RANGE", Start: start, End: end}
+}
+
+// Window is the specification of an OVER clause. Name refers to a window
+// declared with Builder.Window that this one builds upon.
+type Window struct {
+	Name        string
+	PartitionBy []string
+	OrderBy     []OrderBy
+	Frame       *Frame
+}
+
+func (w Window) String() string {
+	stmt, _, _ := w.build()
+	return stmt
+}
+
+// columns lists the columns the window partitions and orders by.
+func (w Window) columns() []string {
+	columns := append([]string{}, w.PartitionBy...)
+	for _, order := range w.OrderBy {
+		columns = append(columns, order.Column)
+	}
+	return columns
+}
+
+// build renders the window with the values bound by its FIELD orderings.
+func (w Window) build() (string, []interface{}, error) {
+	var values []interface{}
+	var err error
+	parts := make([]string, 0, 4)
+	if w.Name != "" {
+		parts = append(parts, w.Name)
+	}
+	if len(w.PartitionBy) > 0 {
+		columns := make([]string, len(w.PartitionBy))
+		for i, column := range w.PartitionBy {
+			columns[i] = quoteKey(column)
+		}
+		parts = append(parts, "PARTITION BY "+strings.Join(columns, ","))
+	}
+	if len(w.OrderBy) > 0 {
+		orders := make([]string, len(w.OrderBy))
+		for i, order := range w.OrderBy {
+			var tmp []interface{}
+			var e error
+			orders[i], tmp, e = buildOrder(order)
+			values = append(values, tmp...)
+			if err == nil {
+				err = e
+			}
+		}
+		parts = append(parts, "ORDER BY "+strings.Join(orders, ","))
+	}
+	if w.Frame != nil {
+		if w.Frame.End == "" {
+			parts = append(parts, w.Frame.Unit+" "+w.Frame.Start)
+		} else {
+			parts = append(parts, w.Frame.Unit+" BETWEEN "+w.Frame.Start+" AND "+w.Frame.End)
+		}
+	}
+	return strings.Join(parts, " "), values, err
+}
+
+// WindowFunction is a function evaluated over a window, selected through
+// SelectWindow.
+type WindowFunction struct {
+	column     string
+	expression string
+	values     []interface{}
+	window     Window
+	named      string
+	alias      string
+}
+
+func RowNumber() WindowFunction {
+	return WindowFunction{expression: "ROW_NUMBER()"}
+}
+
+func Rank() WindowFunction {
+	return WindowFunction{expression: "RANK()"}
+}
+
+func DenseRank() WindowFunction {
+	return WindowFunction{expression: "DENSE_RANK()"}
+}
+
+// Lag reads column offset rows before the current one, falling back to
+// the optional default when there is no such row.
+func Lag(column string, offset int, def ...interface{}) WindowFunction {
+	return offsetFunction("LAG", column, offset, def)
+}
+
+// Lead reads column offset rows after the current one, falling back to
+// the optional default when there is no such row.
+func Lead(column string, offset int, def ...interface{}) WindowFunction {
+	return offsetFunction("LEAD", column, offset, def)
+}
+
+func offsetFunction(function string, column string, offset int, def []interface{}) WindowFunction {
+	if len(def) > 0 {
+		return WindowFunction{
+			column:     column,
+			expression: fmt.Sprintf("%s(%s, %d, ?)", function, quoteKey(column), offset),
+			values:     def[:1],
+		}
+	}
+	return WindowFunction{column: column, expression: fmt.Sprintf("%s(%s, %d)", function, quoteKey(column), offset)}
+}
+
+// Over runs the aggregate as a window function, e.g. a running total with
+// Sum("amount").Over(Window{OrderBy: ...}).
+func (a Aggregate) Over(window Window) WindowFunction {
+	return WindowFunction{column: a.column, expression: a.Expression(), window: window, alias: a.alias}
+}
+
+func (f WindowFunction) Over(window Window) WindowFunction {
+	f.window = window
+	f.named = ""
+	return f
+}
+
+// OverWindow refers to a window declared with Builder.Window by name.
+func (f WindowFunction) OverWindow(name string) WindowFunction {
+	f.named = name
+	return f
+}
+
+func (f WindowFunction) As(alias string) WindowFunction {
+	f.alias = alias
+	return f
+}
+
+func (f WindowFunction) String() string {
+	stmt, _, _ := f.build()
+	return stmt
+}
+
+func (f WindowFunction) build() (string, []interface{}, error) {
+	var s strings.Builder
+	values := f.values
+	var err error
+	s.WriteString(f.expression)
+	s.WriteString(" OVER ")
+	if f.named != "" {
+		s.WriteString(f.named)
+	} else {
+		stmt, tmp, e := f.window.build()
+		s.WriteString("(")
+		s.WriteString(stmt)
+		s.WriteString(")")
+		values = append(values[:len(values):len(values)], tmp...)
+		err = e
+	}
+	if f.alias != "" {
+		s.WriteString(" AS ")
+		s.WriteString(quoteKey(f.alias))
+	}
+	return s.String(), values, err
+}
+
+func (b *builder) SelectWindow(functions ...WindowFunction) Builder {
+	for _, function := range functions {
+		if function.column != "" {
+			b.checkIdentifiers(function.column)
+		}
+		if function.named == "" {
+			b.checkIdentifiers(function.window.columns()...)
+		}
+		stmt, values, err := function.build()
+		b.fail(err)
+		b.Select(stmt)
+		b.selectValues = append(b.selectValues, values...)
+	}
+	return b
+}
+
+func (b *builder) Window(name string, window Window) Builder {
+	if b.windowStatement.Len() > 0 {
+		b.windowStatement.WriteString(", ")
+	}
+	b.windowStatement.WriteString(name)
+	b.windowStatement.WriteString(" AS (")
+	b.checkIdentifiers(window.columns()...)
+	stmt, values, err := window.build()
+	b.fail(err)
+	b.windowStatement.WriteString(stmt)
+	b.windowStatement.WriteString(")")
+	b.windowValues = append(b.windowValues, values...)
+	return b
+}
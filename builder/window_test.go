@@ -0,0 +1,67 @@
+package builder
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestWindow(t *testing.T) {
+	query, values := New().
+		Select("`id`").
+		SelectWindow(
+			RowNumber().OverWindow("w").As("position"),
+			Rank().Over(Window{PartitionBy: []string{"category_id"}, OrderBy: []OrderBy{{Column: "score", Direction: "DESC"}}}).As("rank"),
+			DenseRank().OverWindow("w"),
+			Lag("amount", 1, 0).OverWindow("w").As("previous"),
+			Lead("amount", 2).Over(Window{Name: "w"}),
+			Sum("amount").Over(Window{
+				Name:  "w",
+				Frame: RowsBetween(UnboundedPreceding, CurrentRow),
+			}).As("running_total"),
+			Avg("amount").Over(Window{
+				OrderBy: []OrderBy{{Column: "created_at", Direction: "ASC"}},
+				Frame:   RowsBetween(Preceding(3), Following(3)),
+			}),
+		).
+		From("`sale`").
+		Equal("year", 2024).
+		Window("w", Window{PartitionBy: []string{"s.region"}, OrderBy: []OrderBy{{Column: "created_at", Direction: "ASC"}}}).
+		Order(OrderBy{Column: "id", Direction: "ASC"}).
+		Build()
+	expected := "SELECT `id`," +
+		"ROW_NUMBER() OVER w AS `position`," +
+		"RANK() OVER (PARTITION BY `category_id` ORDER BY `score` DESC) AS `rank`," +
+		"DENSE_RANK() OVER w," +
+		"LAG(`amount`, 1, ?) OVER w AS `previous`," +
+		"LEAD(`amount`, 2) OVER (w)," +
+		"SUM(`amount`) OVER (w ROWS BETWEEN UNBOUNDED PRECEDING AND CURRENT ROW) AS `running_total`," +
+		"AVG(`amount`) OVER (ORDER BY `created_at` ASC ROWS BETWEEN 3 PRECEDING AND 3 FOLLOWING) " +
+		"FROM `sale` WHERE `year` = ? " +
+		"WINDOW w AS (PARTITION BY s.`region` ORDER BY `created_at` ASC) ORDER BY `id` ASC"
+	if query != expected {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{0, 2024}) {
+		t.Fatalf("wrong values %v", values)
+	}
+}
+
+func TestWindowFieldOrder(t *testing.T) {
+	query, values := New().Table("`task`").
+		SelectWindow(RowNumber().Over(Window{OrderBy: []OrderBy{{Column: "status", Fields: []string{"open"}}}})).
+		Window("w", Window{OrderBy: []OrderBy{{Column: "priority", Fields: []string{"high", "low"}}}}).
+		Equal("owner", 3).
+		Build()
+	expected := "SELECT ROW_NUMBER() OVER (ORDER BY Field(`status`,?)) FROM `task` WHERE `owner` = ? WINDOW w AS (ORDER BY Field(`priority`,?,?))"
+	if query != expected {
+		t.Fatalf("query:\n got %q\nwant %q", query, expected)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"open", 3, "high", "low"}) {
+		t.Fatalf("got %#v", values)
+	}
+	b := New().Table("`task`").SelectWindow(Rank().Over(Window{OrderBy: []OrderBy{{Column: "id", Direction: "up"}}}))
+	if !errors.Is(b.Err(), ErrInvalidDirection) {
+		t.Fatalf("got %v", b.Err())
+	}
+}
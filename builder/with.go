@@ -0,0 +1,48 @@
+package builder
+
+import "strings"
+
+// With names the result of other as a common table expression, rendered
+// ahead of the main statement. Name may carry a column list, e.g.
+// "tree(id, parent_id)".
+func (b *builder) With(name string, other Builder) Builder {
+	stmt, values := b.embed(other)
+	return b.cte(name, stmt, values)
+}
+
+// WithRecursive names the UNION ALL of anchor and recursive, the latter
+// referring back to name, and turns the WITH clause into WITH RECURSIVE.
+func (b *builder) WithRecursive(name string, anchor, recursive Builder) Builder {
+	anchorStatement, values := b.embed(anchor)
+	recursiveStatement, tmp := b.embed(recursive)
+	b.recursive = true
+	return b.cte(name, anchorStatement+" UNION ALL "+recursiveStatement, append(values, tmp...))
+}
+
+func (b *builder) cte(name, stmt string, values []interface{}) Builder {
+	if b.withStatement.Len() > 0 {
+		b.withStatement.WriteString(", ")
+	}
+	b.withStatement.WriteString(name)
+	b.withStatement.WriteString(" AS (")
+	b.withStatement.WriteString(stmt)
+	b.withStatement.WriteString(")")
+	b.withValues = append(b.withValues, values...)
+	return b
+}
+
+// buildWith writes the WITH clause to query and returns its values, which
+// precede every other value of the statement.
+func (b *builder) buildWith(query *strings.Builder) []interface{} {
+	if b.withStatement.Len() == 0 {
+		return nil
+	}
+	query.WriteString("WITH ")
+	if b.recursive {
+		query.WriteString("RECURSIVE ")
+	}
+	query.WriteString(b.withStatement.String())
+	query.WriteString(" ")
+	values := make([]interface{}, 0, len(b.withValues))
+	return append(values, b.withValues...)
+}
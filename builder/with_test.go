@@ -0,0 +1,45 @@
+package builder
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWith(t *testing.T) {
+	paid := New().Select("`user_id`,SUM(`amount`) AS total").From("`order`").Equal("status", "paid").Group("user_id")
+	query, values := New().
+		With("totals", paid).
+		Select("u.`name`,t.total").
+		From("`user`", "u").
+		Join("totals", "t.`user_id` = u.`id`", "t").
+		Equal("u.`active`", true).
+		Build()
+	expected := "WITH totals AS (SELECT `user_id`,SUM(`amount`) AS total FROM `order` WHERE `status` = ? GROUP BY `user_id`) " +
+		"SELECT u.`name`,t.total FROM `user` u JOIN totals t  ON t.`user_id` = u.`id` WHERE u.`active` = ?"
+	if query != expected {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{"paid", true}) {
+		t.Fatalf("wrong values %v", values)
+	}
+}
+
+func TestWithRecursive(t *testing.T) {
+	anchor := New().Select("`id`,`parent_id`").From("`category`").Equal("id", 5)
+	recursive := New().Select("c.`id`,c.`parent_id`").From("`category`", "c").Join("tree", "tree.`id` = c.`parent_id`")
+	query, values := New().
+		WithRecursive("tree(`id`,`parent_id`)", anchor, recursive).
+		Table("`category`").
+		Update(map[string]interface{}{"archived": 1}).
+		InSubquery("id", New().Select("`id`").From("tree")).
+		Build()
+	expected := "WITH RECURSIVE tree(`id`,`parent_id`) AS (SELECT `id`,`parent_id` FROM `category` WHERE `id` = ? " +
+		"UNION ALL SELECT c.`id`,c.`parent_id` FROM `category` c JOIN tree  ON tree.`id` = c.`parent_id`) " +
+		"UPDATE `category` SET `archived`=? WHERE `id` IN (SELECT `id` FROM tree)"
+	if query != expected {
+		t.Fatalf("got %q", query)
+	}
+	if !reflect.DeepEqual(values, []interface{}{5, 1}) {
+		t.Fatalf("wrong values %v", values)
+	}
+}
@@ -3,10 +3,16 @@ package gosl
 import (
 	"context"
 	_sql "database/sql"
+	"errors"
 
 	"github.com/jmoiron/sqlx"
+	"github.com/louvri/gosl/builder"
 )
 
+// ErrNoTransaction is returned when a locking read is run outside of a
+// transaction, where its locks would be released immediately
+var ErrNoTransaction = errors.New("no active transaction")
+
 type Queryable struct {
 	db  *sqlx.DB
 	tx  *sqlx.Tx
@@ -284,3 +290,98 @@ func (qtx *Queryable) StmtxContext(ctx context.Context, stmt interface{}) *sqlx.
 		return qtx.tx.StmtxContext(ctx, stmt)
 	}
 }
+
+// ExecBatches run ExecBatchesContext with background context
+func (qtx *Queryable) ExecBatches(batches []builder.Batch) (int64, error) {
+	return qtx.ExecBatchesContext(context.Background(), batches)
+}
+
+// ExecBatchesContext executes batches in order inside the current transaction,
+// or inside a transaction of its own when none is active, and returns the total
+// of affected rows
+func (qtx *Queryable) ExecBatchesContext(ctx context.Context, batches []builder.Batch) (int64, error) {
+	tx := qtx.tx
+	if tx == nil {
+		var err error
+		tx, err = qtx.db.BeginTxx(ctx, nil)
+		if err != nil {
+			return 0, err
+		}
+	}
+	var total int64
+	for _, batch := range batches {
+		result, err := tx.ExecContext(ctx, batch.Query, batch.Args...)
+		if err != nil {
+			if qtx.tx == nil {
+				_ = tx.Rollback()
+			}
+			return total, err
+		}
+		if n, err := result.RowsAffected(); err == nil {
+			total += n
+		}
+	}
+	if qtx.tx == nil {
+		return total, tx.Commit()
+	}
+	return total, nil
+}
+
+// SelectBuilderContext builds b and runs it with SelectContext
+func (qtx *Queryable) SelectBuilderContext(ctx context.Context, dest interface{}, b builder.Builder) error {
+	query, args, err := qtx.build(b)
+	if err != nil {
+		return err
+	}
+	return qtx.SelectContext(ctx, dest, query, args...)
+}
+
+// GetBuilderContext builds b and runs it with GetContext
+func (qtx *Queryable) GetBuilderContext(ctx context.Context, dest interface{}, b builder.Builder) error {
+	query, args, err := qtx.build(b)
+	if err != nil {
+		return err
+	}
+	return qtx.GetContext(ctx, dest, query, args...)
+}
+
+// ExecBuilderContext builds b and runs it with ExecContext
+func (qtx *Queryable) ExecBuilderContext(ctx context.Context, b builder.Builder) (_sql.Result, error) {
+	query, args, err := qtx.build(b)
+	if err != nil {
+		return nil, err
+	}
+	return qtx.ExecContext(ctx, query, args...)
+}
+
+// PaginateContext selects the page of b into dest and returns the total of
+// rows across all pages, counted with BuildCount
+func (qtx *Queryable) PaginateContext(ctx context.Context, dest interface{}, b builder.Builder) (int64, error) {
+	query, args, err := qtx.build(b)
+	if err != nil {
+		return 0, err
+	}
+	countQuery, countArgs := b.BuildCount()
+	if err := b.Err(); err != nil {
+		return 0, err
+	}
+	var total int64
+	if err := qtx.GetContext(ctx, &total, countQuery, countArgs...); err != nil {
+		return 0, err
+	}
+	if total == 0 {
+		return 0, nil
+	}
+	return total, qtx.SelectContext(ctx, dest, query, args...)
+}
+
+func (qtx *Queryable) build(b builder.Builder) (string, []interface{}, error) {
+	if b.Locked() && qtx.tx == nil {
+		return "", nil, ErrNoTransaction
+	}
+	query, args := b.Build()
+	if err := b.Err(); err != nil {
+		return "", nil, err
+	}
+	return query, args, nil
+}